@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"solid-software.test-task/pkg/framework/config"
+)
+
+const (
+	configUsage = "usage: api-server config validate|print"
+)
+
+// runConfigCommand runs the "config" subcommand of the arguments and returns the exit code:
+//
+//	config validate  checks the config and lists all its problems
+//	config print     prints the effective config with the secrets redacted
+func runConfigCommand(options config.Options, args []string) int {
+	if len(args) != 2 || args[0] != "config" || (args[1] != "validate" && args[1] != "print") { //nolint:gomnd
+		fmt.Fprintln(os.Stderr, configUsage)
+
+		return 2 //nolint:gomnd
+	}
+
+	if err := config.Init(options); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+
+		return 1
+	}
+
+	if args[1] == "validate" {
+		fmt.Fprintln(os.Stdout, "config is valid")
+
+		return 0
+	}
+
+	if err := printConfig(os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+
+		return 1
+	}
+
+	return 0
+}
+
+func printConfig(writer io.Writer) error {
+	encoder := yaml.NewEncoder(writer)
+	encoder.SetIndent(2) //nolint:gomnd
+
+	if err := encoder.Encode(config.RedactedSettings()); err != nil {
+		return fmt.Errorf("print config: %w", err)
+	}
+
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("print config: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"solid-software.test-task/pkg/framework/config"
+)
+
+type (
+	// overrides collects the repeated "--set key=value" flags.
+	overrides map[string]string
+)
+
+var (
+	errInvalidOverride = errors.New("override must be key=value")
+)
+
+// parseFlags parses the flags selecting the config sources and returns the remaining arguments.
+// The flags are accepted after the "config" subcommand as well, e.g. "config validate --env prod".
+func parseFlags(args []string) (config.Options, []string, error) {
+	var options config.Options
+
+	values := overrides{}
+	flags := flag.NewFlagSet("api-server", flag.ContinueOnError)
+	flags.StringVar(&options.File, "config", "", "path of the base config file (default ./config.yaml)")
+	flags.StringVar(&options.Env, "env", "", "environment profile layered over the base file, e.g. prod for config.prod.yaml (default $SSTT_ENV)")
+	flags.Var(values, "set", "config value overriding every other source, e.g. --set webService.port=8080; may be repeated.\n"+
+		"The values of the string keys are taken as given; the others are parsed as YAML, e.g. [a, b] is a list")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: api-server [flags] [config validate|print]")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return options, nil, fmt.Errorf("parse flags: %w", err)
+	}
+
+	rest := flags.Args()
+
+	if len(rest) > 2 && rest[0] == "config" { //nolint:gomnd
+		command := rest[:2:2]
+
+		if err := flags.Parse(rest[2:]); err != nil {
+			return options, nil, fmt.Errorf("parse flags: %w", err)
+		}
+
+		rest = append(command, flags.Args()...)
+	}
+
+	options.Overrides = values
+
+	return options, rest, nil
+}
+
+func (o overrides) String() string {
+	pairs := make([]string, 0, len(o))
+	for key, value := range o {
+		pairs = append(pairs, key+"="+value)
+	}
+
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ",")
+}
+
+func (o overrides) Set(value string) error {
+	key, v, ok := strings.Cut(value, "=")
+	if !ok || strings.TrimSpace(key) == "" {
+		return fmt.Errorf("%w: %q", errInvalidOverride, value)
+	}
+
+	o[strings.TrimSpace(key)] = v
+
+	return nil
+}
@@ -1,18 +1,48 @@
 package main
 
 import (
+	"context"
 	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"solid-software.test-task/pkg/app"
 	"solid-software.test-task/pkg/framework/config"
+	"solid-software.test-task/pkg/framework/logger"
 )
 
 func main() {
-	if err := config.Init(); err != nil {
+	options, args, err := parseFlags(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+
+	if err != nil {
+		// the flag set has already printed the error with the usage.
+		os.Exit(2) //nolint:gomnd
+	}
+
+	if len(args) > 0 {
+		os.Exit(runConfigCommand(options, args))
+	}
+
+	// the config problems are listed at once rather than panicking at the first one.
+	if err = config.Init(options); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if err := logger.Init(config.NewConfig()); err != nil {
 		panic(err)
 	}
 
-	if err := app.Run(); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err = app.Run(ctx); err != nil {
 		if !errors.Is(err, app.ErrServerClosed) {
 			panic(err)
 		}
@@ -1,13 +1,21 @@
 package app
 
 import (
+	"context"
 	"fmt"
 
 	"solid-software.test-task/pkg/app/di"
+	"solid-software.test-task/pkg/domain/session"
+	"solid-software.test-task/pkg/framework/config"
+	"solid-software.test-task/pkg/framework/tracing"
 	"solid-software.test-task/pkg/framework/webservice"
+	"solid-software.test-task/pkg/infra/api/account"
 	"solid-software.test-task/pkg/infra/api/healthz"
 	"solid-software.test-task/pkg/infra/api/token"
+	tokendi "solid-software.test-task/pkg/infra/api/token/di"
 	"solid-software.test-task/pkg/infra/api/user"
+	userdi "solid-software.test-task/pkg/infra/api/user/di"
+	"solid-software.test-task/pkg/infra/db"
 )
 
 var (
@@ -17,12 +25,31 @@ var (
 
 // Run bootstraps and starts the web service.
 // It first initializes a new web service instance,
-// then registers the necessary endpoints and finally starts the service.
-func Run() error {
+// then registers the necessary endpoints and lifecycle hooks and finally starts the service.
+// The service is stopped gracefully when the context is canceled.
+func Run(ctx context.Context) error {
+	shutdownTracing, err := tracing.Init(ctx, config.NewConfig())
+	if err != nil {
+		return fmt.Errorf("failed to initialize tracing: %w", err)
+	}
+
 	service := di.InitializeNewWebService()
-	service.RegisterEndpoints(token.NewTokenAPI(), user.NewUserAPI(), healthz.NewHealthzAPI())
+	// the hasher and the session service follow the config reloads, so they are built once and shared by the APIs.
+	hasher := userdi.InitializeHasher()
+	sessions := tokendi.InitializeSessionService()
+	service.RegisterEndpoints(
+		token.NewTokenAPI(hasher, sessions), account.NewAccountAPI(hasher, sessions),
+		user.NewUserAPI(hasher, sessions), healthz.NewHealthzAPI(),
+	)
+	service.Go("refresh token sweeper", session.Sweeper(sessions))
+	service.OnStop("tracing", func(ctx context.Context) error {
+		return shutdownTracing(ctx)
+	})
+	service.OnStop("database", func(context.Context) error {
+		return db.Close()
+	})
 
-	err := service.Run()
+	err = service.Run(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to run the service: %w", err)
 	}
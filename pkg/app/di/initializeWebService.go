@@ -9,9 +9,12 @@ import (
 	"solid-software.test-task/pkg/framework/config"
 	"solid-software.test-task/pkg/framework/webservice"
 	"solid-software.test-task/pkg/framework/webservice/interfaces"
+	"solid-software.test-task/pkg/infra/db"
+	"solid-software.test-task/pkg/infra/db/denyliststore"
+	"solid-software.test-task/pkg/infra/db/idempotencystore"
 )
 
 func InitializeNewWebService() interfaces.WebService {
-	wire.Build(webservice.New, config.NewConfig)
+	wire.Build(webservice.New, config.NewConfig, idempotencystore.New, denyliststore.New, db.GetRawDBConnection)
 	return nil
 }
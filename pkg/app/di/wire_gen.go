@@ -10,12 +10,18 @@ import (
 	"solid-software.test-task/pkg/framework/config"
 	"solid-software.test-task/pkg/framework/webservice"
 	"solid-software.test-task/pkg/framework/webservice/interfaces"
+	"solid-software.test-task/pkg/infra/db"
+	"solid-software.test-task/pkg/infra/db/denyliststore"
+	"solid-software.test-task/pkg/infra/db/idempotencystore"
 )
 
 // Injectors from initializeWebService.go:
 
 func InitializeNewWebService() interfaces.WebService {
 	configConfig := config.NewConfig()
-	webService := webservice.New(configConfig)
+	gormDB := db.GetRawDBConnection()
+	store := idempotencystore.New(gormDB)
+	denylist := denyliststore.New(gormDB)
+	webService := webservice.New(configConfig, store, denylist)
 	return webService
 }
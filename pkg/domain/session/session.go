@@ -0,0 +1,267 @@
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"solid-software.test-task/pkg/framework/config"
+	"solid-software.test-task/pkg/framework/logger"
+	"solid-software.test-task/pkg/framework/tracing"
+	"solid-software.test-task/pkg/framework/webservice/jwt"
+	"solid-software.test-task/pkg/infra/db/models"
+)
+
+const (
+	refreshTokenLength = 32
+)
+
+type (
+	// Service manages the login sessions: the rotating refresh tokens and the revocation of the access tokens.
+	// A session starts at login and lives as long as its refresh token is rotated before it expires.
+	Service interface {
+		// Start issues the first refresh token of a new session of the user.
+		Start(ctx context.Context, userID uint, username string) (string, error)
+		// Refresh exchanges the refresh token for the next one of its session and returns the session.
+		// Presenting a token which has been exchanged already ends its session and returns ErrTokenReused.
+		Refresh(ctx context.Context, token string) (string, *Session, error)
+		// End ends the session of the refresh token. The unknown tokens are ignored.
+		End(ctx context.Context, token string) error
+		// RevokeAccessToken denies the verified access token until it expires.
+		RevokeAccessToken(ctx context.Context, claim *jwt.SampleClaim) error
+		// EndAll ends all the sessions of the user and denies the access tokens issued to the user so far.
+		EndAll(ctx context.Context, userID uint) error
+		// DeleteExpired removes the refresh tokens expired before the given time.
+		DeleteExpired(ctx context.Context, now time.Time) error
+	}
+
+	// Session is the user a refresh token is issued to.
+	Session struct {
+		UserID   uint
+		Username string
+	}
+
+	service struct {
+		db           *gorm.DB
+		denylist     jwt.Denylist
+		refreshToken *config.Watched[config.RefreshToken]
+		accessToken  *config.Watched[config.JWT]
+	}
+)
+
+var (
+	// ErrInvalidToken is returned when the refresh token is unknown, expired or revoked.
+	ErrInvalidToken = errors.New("invalid refresh token")
+	// ErrTokenReused is returned when a refresh token is presented again after it has been exchanged.
+	// Either the client or an attacker holds a stolen copy, so the whole session is ended.
+	ErrTokenReused = errors.New("refresh token reused, the session is ended")
+)
+
+// NewService creates a new session service keeping the refresh tokens in the database.
+// The refresh tokens live for the "auth.refreshToken" ttl; the subject denylist entries
+// are kept for the access token lifetime of the "webService.jwt" section.
+func NewService(
+	db *gorm.DB, denylist jwt.Denylist,
+	refreshToken *config.Watched[config.RefreshToken], accessToken *config.Watched[config.JWT],
+) Service {
+	return &service{db: db, denylist: denylist, refreshToken: refreshToken, accessToken: accessToken}
+}
+
+// Start issues the first refresh token of a new session of the user.
+func (s *service) Start(ctx context.Context, userID uint, username string) (string, error) {
+	ctx, span := tracing.StartSpan(ctx, "SessionService.Start")
+	defer span.End()
+
+	return s.issue(s.db.WithContext(ctx), uuid.NewString(), userID, username)
+}
+
+// Refresh exchanges the refresh token for the next one of its session and returns the session.
+// Only one of the concurrent refreshes with the same token succeeds; the others count as a reuse.
+func (s *service) Refresh(ctx context.Context, token string) (string, *Session, error) {
+	ctx, span := tracing.StartSpan(ctx, "SessionService.Refresh")
+	defer span.End()
+
+	current, err := s.find(ctx, token)
+	if err != nil {
+		return "", nil, err
+	}
+
+	now := time.Now()
+
+	switch {
+	case current.RevokedAt != nil, !current.ExpiresAt.After(now):
+		return "", nil, ErrInvalidToken
+	case current.RotatedAt != nil:
+		return "", nil, s.endReused(ctx, current)
+	}
+
+	var next string
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(current).Where("rotated_at IS NULL AND revoked_at IS NULL").Update("rotated_at", now)
+		if result.Error != nil {
+			return fmt.Errorf("rotating refresh token: %w", result.Error)
+		}
+
+		if result.RowsAffected == 0 {
+			return ErrTokenReused
+		}
+
+		var issueErr error
+		next, issueErr = s.issue(tx, current.FamilyID, current.UserID, current.Username)
+
+		return issueErr
+	})
+	if err != nil {
+		if errors.Is(err, ErrTokenReused) {
+			return "", nil, s.endReused(ctx, current)
+		}
+
+		return "", nil, err
+	}
+
+	return next, &Session{UserID: current.UserID, Username: current.Username}, nil
+}
+
+// End ends the session of the refresh token. The unknown tokens are ignored.
+func (s *service) End(ctx context.Context, token string) error {
+	ctx, span := tracing.StartSpan(ctx, "SessionService.End")
+	defer span.End()
+
+	current, err := s.find(ctx, token)
+	if err != nil {
+		if errors.Is(err, ErrInvalidToken) {
+			return nil
+		}
+
+		return err
+	}
+
+	return s.revoke(ctx, "family_id = ?", current.FamilyID)
+}
+
+// RevokeAccessToken denies the verified access token until it expires.
+// The tokens issued without an ID can't be revoked one by one; they are left to expire.
+func (s *service) RevokeAccessToken(ctx context.Context, claim *jwt.SampleClaim) error {
+	ctx, span := tracing.StartSpan(ctx, "SessionService.RevokeAccessToken")
+	defer span.End()
+
+	if claim.ID == "" {
+		return nil
+	}
+
+	return s.denylist.Revoke(ctx, claim.ID, claim.ExpiresAt)
+}
+
+// EndAll ends all the sessions of the user and denies the access tokens issued to the user so far,
+// e.g. when the password is changed or the user is deleted.
+func (s *service) EndAll(ctx context.Context, userID uint) error {
+	ctx, span := tracing.StartSpan(ctx, "SessionService.EndAll")
+	defer span.End()
+
+	if err := s.revoke(ctx, "user_id = ?", userID); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	accessTokenTTL := time.Duration(s.accessToken.Load().TokenExpirationTimeInMinutes) * time.Minute
+
+	err := s.denylist.RevokeSubject(ctx, strconv.FormatUint(uint64(userID), 10), now, now.Add(accessTokenTTL))
+	if err != nil {
+		return fmt.Errorf("revoking access tokens: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteExpired removes the refresh tokens expired before the given time.
+func (s *service) DeleteExpired(ctx context.Context, now time.Time) error {
+	ctx, span := tracing.StartSpan(ctx, "SessionService.DeleteExpired")
+	defer span.End()
+
+	err := s.db.WithContext(ctx).Where("expires_at < ?", now).Delete(&models.RefreshToken{}).Error
+	if err != nil {
+		return fmt.Errorf("deleting expired refresh tokens: %w", err)
+	}
+
+	return nil
+}
+
+func (s *service) find(ctx context.Context, token string) (*models.RefreshToken, error) {
+	var current models.RefreshToken
+
+	err := s.db.WithContext(ctx).First(&current, "token_hash = ?", hashToken(token)).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidToken
+		}
+
+		return nil, fmt.Errorf("retrieving refresh token: %w", err)
+	}
+
+	return &current, nil
+}
+
+// endReused ends the session of the reused refresh token and returns ErrTokenReused.
+func (s *service) endReused(ctx context.Context, reused *models.RefreshToken) error {
+	logger.FromContext(ctx).Warn("refresh token reused, ending the session",
+		slog.Uint64("userID", uint64(reused.UserID)), slog.String("session", reused.FamilyID))
+
+	if err := s.revoke(ctx, "family_id = ?", reused.FamilyID); err != nil {
+		return err
+	}
+
+	return ErrTokenReused
+}
+
+func (s *service) revoke(ctx context.Context, query string, args ...any) error {
+	err := s.db.WithContext(ctx).Model(&models.RefreshToken{}).
+		Where(query, args...).Where("revoked_at IS NULL").
+		Update("revoked_at", time.Now()).Error
+	if err != nil {
+		return fmt.Errorf("revoking refresh tokens: %w", err)
+	}
+
+	return nil
+}
+
+// issue stores the hash of a new random refresh token of the session and returns the token.
+func (s *service) issue(db *gorm.DB, familyID string, userID uint, username string) (string, error) {
+	random := make([]byte, refreshTokenLength)
+	if _, err := rand.Read(random); err != nil {
+		return "", fmt.Errorf("generate refresh token: %w", err)
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(random)
+
+	err := db.Create(&models.RefreshToken{
+		TokenHash: hashToken(token),
+		FamilyID:  familyID,
+		UserID:    userID,
+		Username:  username,
+		ExpiresAt: time.Now().Add(s.refreshToken.Load().TTL),
+	}).Error
+	if err != nil {
+		return "", fmt.Errorf("saving refresh token: %w", err)
+	}
+
+	return token, nil
+}
+
+// hashToken hashes the refresh token with SHA-256. The tokens are random, so they need no salt
+// nor a slow hash, and the hash can be looked up directly.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,264 @@
+package session_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+
+	"solid-software.test-task/pkg/domain/session"
+	"solid-software.test-task/pkg/framework/config"
+	"solid-software.test-task/pkg/infra/db/denyliststore"
+	"solid-software.test-task/pkg/infra/db/models"
+)
+
+const (
+	concurrentRefreshes = 8
+)
+
+func TestServiceRefresh(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		ttl  time.Duration
+		// exchange uses the session started with the first token and returns the token to refresh.
+		exchange func(t *testing.T, service session.Service, first string) string
+		wantErr  error
+		// wantEnded requires all the refresh tokens of the session to be revoked after the refresh.
+		wantEnded bool
+		// invalidAfter returns the tokens of the session which must be invalid after the refresh.
+		invalidAfter func(first, next string) []string
+	}{
+		{
+			name:     "first token",
+			ttl:      time.Hour,
+			exchange: func(_ *testing.T, _ session.Service, first string) string { return first },
+			invalidAfter: func(first, _ string) []string {
+				return []string{first}
+			},
+		},
+		{
+			name:     "rotated token",
+			ttl:      time.Hour,
+			exchange: refreshed,
+		},
+		{
+			name: "reused token ends the session",
+			ttl:  time.Hour,
+			exchange: func(t *testing.T, service session.Service, first string) string {
+				refreshed(t, service, first)
+
+				return first
+			},
+			wantErr:   session.ErrTokenReused,
+			wantEnded: true,
+		},
+		{
+			name: "reused rotated token ends the session",
+			ttl:  time.Hour,
+			exchange: func(t *testing.T, service session.Service, first string) string {
+				second := refreshed(t, service, first)
+				refreshed(t, service, second)
+
+				return second
+			},
+			wantErr:   session.ErrTokenReused,
+			wantEnded: true,
+		},
+		{
+			name: "ended session",
+			ttl:  time.Hour,
+			exchange: func(t *testing.T, service session.Service, first string) string {
+				t.Helper()
+
+				if err := service.End(context.Background(), first); err != nil {
+					t.Fatalf("End() error = %v", err)
+				}
+
+				return first
+			},
+			wantErr: session.ErrInvalidToken,
+		},
+		{
+			name:     "expired token",
+			ttl:      -time.Second,
+			exchange: func(_ *testing.T, _ session.Service, first string) string { return first },
+			wantErr:  session.ErrInvalidToken,
+		},
+		{
+			name:     "unknown token",
+			ttl:      time.Hour,
+			exchange: func(_ *testing.T, _ session.Service, _ string) string { return "unknown" },
+			wantErr:  session.ErrInvalidToken,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			service, db := newService(t, test.ttl)
+
+			first, err := service.Start(ctx, 1, "admin")
+			if err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+
+			next, got, err := service.Refresh(ctx, test.exchange(t, service, first))
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("Refresh() error = %v, want %v", err, test.wantErr)
+			}
+
+			if test.wantEnded {
+				assertSessionEnded(t, db)
+			}
+
+			if test.wantErr != nil {
+				return
+			}
+
+			if got.UserID != 1 || got.Username != "admin" {
+				t.Errorf("Refresh() session = %+v, want the user 1 admin", got)
+			}
+
+			if test.invalidAfter != nil {
+				for _, token := range test.invalidAfter(first, next) {
+					if _, _, err = service.Refresh(ctx, token); err == nil {
+						t.Errorf("Refresh() of an exchanged token succeeded")
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestServiceRefreshConcurrently(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	service, _ := newService(t, time.Hour)
+
+	first, err := service.Start(ctx, 1, "admin")
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	var (
+		wg    sync.WaitGroup
+		start = make(chan struct{})
+		nexts = make([]string, concurrentRefreshes)
+		errs  = make([]error, concurrentRefreshes)
+	)
+
+	for i := range nexts {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			<-start
+
+			nexts[i], _, errs[i] = service.Refresh(ctx, first)
+		}(i)
+	}
+
+	close(start)
+	wg.Wait()
+
+	var (
+		succeeded []string
+		reused    int
+	)
+
+	for i, err := range errs {
+		switch {
+		case err == nil:
+			succeeded = append(succeeded, nexts[i])
+		case errors.Is(err, session.ErrTokenReused):
+			reused++
+		case !errors.Is(err, session.ErrInvalidToken):
+			t.Fatalf("Refresh() error = %v", err)
+		}
+	}
+
+	if len(succeeded) != 1 {
+		t.Fatalf("Refresh() succeeded %d times, want once", len(succeeded))
+	}
+
+	if reused == 0 {
+		t.Fatalf("Refresh() reported no reuse of the token")
+	}
+
+	// The concurrent refreshes count as a reuse, so the session is ended together with the winning token.
+	if _, _, err = service.Refresh(ctx, succeeded[0]); !errors.Is(err, session.ErrInvalidToken) {
+		t.Errorf("Refresh() of the winning token error = %v, want %v", err, session.ErrInvalidToken)
+	}
+}
+
+// refreshed refreshes the token and returns the next one of its session.
+func refreshed(t *testing.T, service session.Service, token string) string {
+	t.Helper()
+
+	next, _, err := service.Refresh(context.Background(), token)
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	return next
+}
+
+// assertSessionEnded checks that all the refresh tokens of the session are revoked.
+func assertSessionEnded(t *testing.T, db *gorm.DB) {
+	t.Helper()
+
+	var active int64
+
+	err := db.Model(&models.RefreshToken{}).Where("revoked_at IS NULL").Count(&active).Error
+	if err != nil {
+		t.Fatalf("counting refresh tokens: %v", err)
+	}
+
+	if active != 0 {
+		t.Errorf("%d refresh tokens of the reused session are still active", active)
+	}
+}
+
+func newService(t *testing.T, ttl time.Duration) (session.Service, *gorm.DB) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("opening database: %v", err)
+	}
+
+	if err = db.AutoMigrate(&models.RefreshToken{}, &models.RevokedToken{}); err != nil {
+		t.Fatalf("migrating database: %v", err)
+	}
+
+	// SQLite allows a single writer; the concurrent refreshes wait for the connection instead of failing as busy.
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("retrieving database: %v", err)
+	}
+
+	sqlDB.SetMaxOpenConns(1)
+
+	cfg := config.NewConfig()
+	refreshToken := config.NewWatched(cfg, "auth.refreshToken", func(config.Config) config.RefreshToken {
+		return config.RefreshToken{TTL: ttl}
+	})
+	accessToken := config.NewWatched(cfg, "webService.jwt", func(config.Config) config.JWT {
+		return config.JWT{TokenExpirationTimeInMinutes: 60}
+	})
+
+	return session.NewService(db, denyliststore.New(db), refreshToken, accessToken), db
+}
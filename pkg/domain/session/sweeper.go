@@ -0,0 +1,32 @@
+package session
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"solid-software.test-task/pkg/framework/logger"
+)
+
+const (
+	sweepInterval = 10 * time.Minute
+)
+
+// Sweeper returns a background worker which removes the expired refresh tokens periodically.
+func Sweeper(service Service) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		ticker := time.NewTicker(sweepInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				if err := service.DeleteExpired(ctx, now); err != nil {
+					logger.Default().Error("delete expired refresh tokens", slog.Any("error", err))
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"solid-software.test-task/pkg/framework/logger"
+	"solid-software.test-task/pkg/framework/password"
+	"solid-software.test-task/pkg/framework/tracing"
+	"solid-software.test-task/pkg/infra/db/models"
+)
+
+type (
+	// Credentials are the username and the password a user logs in with.
+	Credentials struct {
+		Username string `json:"username" xml:"username" validate:"required,min=3,max=64,pattern=^[A-Za-z0-9._@-]+$"`
+		Password string `json:"password" xml:"password" validate:"required"`
+	}
+
+	// PasswordChange replaces the password of the authenticated user.
+	PasswordChange struct {
+		CurrentPassword string `json:"currentPassword" xml:"currentPassword" validate:"required"`
+		NewPassword     string `json:"newPassword" xml:"newPassword" validate:"required"`
+	}
+)
+
+var (
+	// ErrInvalidCredentials is returned when the username or the password doesn't match.
+	// It doesn't tell which one, so the usernames can't be enumerated.
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	// ErrUsernameTaken is returned when the username belongs to another user.
+	ErrUsernameTaken = errors.New("username is already taken")
+	// ErrNoCredentials is returned when the password of a user without credentials is changed.
+	ErrNoCredentials = errors.New("user has no credentials")
+)
+
+// SetCredentials sets the username and the password of the user, replacing the previous ones.
+// The password must satisfy the password policy.
+func (s *service) SetCredentials(ctx context.Context, userID uint, credentials Credentials) error {
+	ctx, span := tracing.StartSpan(ctx, "UserService.SetCredentials")
+	defer span.End()
+
+	if _, err := s.GetByID(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := s.hasher.Check(credentials.Username, credentials.Password); err != nil {
+		return fmt.Errorf("check password: %w", err)
+	}
+
+	var taken int64
+
+	err := s.DB.WithContext(ctx).Model(&models.Credential{}).
+		Where("username = ? AND user_id <> ?", credentials.Username, userID).Count(&taken).Error
+	if err != nil {
+		return fmt.Errorf("checking username: %w", err)
+	}
+
+	if taken > 0 {
+		return ErrUsernameTaken
+	}
+
+	hash, err := s.hasher.Hash(credentials.Password)
+	if err != nil {
+		return err
+	}
+
+	credential := models.Credential{UserID: userID, Username: credentials.Username, PasswordHash: hash}
+
+	err = s.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"username", "password_hash", "updated_at"}),
+	}).Create(&credential).Error
+	if err != nil {
+		return fmt.Errorf("saving credentials: %w", err)
+	}
+
+	return nil
+}
+
+// ChangePassword replaces the password of the user after verifying the current one.
+func (s *service) ChangePassword(ctx context.Context, userID uint, change PasswordChange) error {
+	ctx, span := tracing.StartSpan(ctx, "UserService.ChangePassword")
+	defer span.End()
+
+	var credential models.Credential
+
+	err := s.DB.WithContext(ctx).First(&credential, "user_id = ?", userID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNoCredentials
+		}
+
+		return fmt.Errorf("retrieving credentials: %w", err)
+	}
+
+	if _, err = s.hasher.Verify(credential.PasswordHash, change.CurrentPassword); err != nil {
+		if errors.Is(err, password.ErrMismatch) {
+			return ErrInvalidCredentials
+		}
+
+		return fmt.Errorf("verify password: %w", err)
+	}
+
+	if err = s.hasher.Check(credential.Username, change.NewPassword); err != nil {
+		return fmt.Errorf("check password: %w", err)
+	}
+
+	return s.updatePasswordHash(ctx, &credential, change.NewPassword)
+}
+
+// Authenticate returns the user the credentials belong to, or ErrInvalidCredentials.
+// The hashes of the outdated algorithm or parameters are replaced after a successful login.
+func (s *service) Authenticate(ctx context.Context, credentials Credentials) (*Entity, error) {
+	ctx, span := tracing.StartSpan(ctx, "UserService.Authenticate")
+	defer span.End()
+
+	var credential models.Credential
+
+	err := s.DB.WithContext(ctx).First(&credential, "username = ?", credentials.Username).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("retrieving credentials: %w", err)
+		}
+
+		// the password is hashed anyway, so the unknown usernames take as long as the known ones.
+		// The passwords too long to hash are rejected without hashing for the known usernames as well.
+		if _, err = s.hasher.Hash(credentials.Password); err != nil && !errors.Is(err, password.ErrTooLong) {
+			return nil, err
+		}
+
+		return nil, ErrInvalidCredentials
+	}
+
+	rehash, err := s.hasher.Verify(credential.PasswordHash, credentials.Password)
+	if err != nil {
+		if errors.Is(err, password.ErrMismatch) {
+			return nil, ErrInvalidCredentials
+		}
+
+		return nil, fmt.Errorf("verify password: %w", err)
+	}
+
+	entity, err := s.GetByID(ctx, credential.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidCredentials
+		}
+
+		return nil, err
+	}
+
+	if rehash {
+		// the login succeeds even if the outdated hash can't be replaced this time.
+		if err = s.updatePasswordHash(ctx, &credential, credentials.Password); err != nil {
+			logger.FromContext(ctx).Warn("rehash password", slog.Uint64("userID", uint64(credential.UserID)), slog.Any("error", err))
+		}
+	}
+
+	return entity, nil
+}
+
+// DeleteByID deletes the user together with its credentials, so the username may be taken again.
+func (s *service) DeleteByID(ctx context.Context, userID uint) error {
+	err := s.DB.WithContext(ctx).Delete(&models.Credential{}, "user_id = ?", userID).Error
+	if err != nil {
+		return fmt.Errorf("deleting credentials: %w", err)
+	}
+
+	return s.BaseStore.DeleteByID(ctx, userID)
+}
+
+func (s *service) updatePasswordHash(ctx context.Context, credential *models.Credential, newPassword string) error {
+	hash, err := s.hasher.Hash(newPassword)
+	if err != nil {
+		return err
+	}
+
+	err = s.DB.WithContext(ctx).Model(credential).Update("password_hash", hash).Error
+	if err != nil {
+		return fmt.Errorf("updating password hash: %w", err)
+	}
+
+	return nil
+}
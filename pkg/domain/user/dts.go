@@ -2,6 +2,7 @@ package user
 
 import (
 	"context"
+	"encoding/xml"
 	"time"
 
 	"gorm.io/gorm"
@@ -13,16 +14,22 @@ type (
 	// Entity represents a user domain model.
 	// It is used to transfer data between the domain and the infrastructure layers.
 	Entity struct {
-		ID        uint      `json:"id"`
-		CreatedAt time.Time `json:"createdAt"`
-		UpdatedAt time.Time `json:"updatedAt"`
-		Name      string    `json:"name,omitempty"`
-		Surname   string    `json:"surname,omitempty"`
-		Phone     string    `json:"phone,omitempty"`
-		Address   string    `json:"address,omitempty"`
+		XMLName   xml.Name  `json:"-" xml:"user"`
+		ID        uint      `json:"id" xml:"id"`
+		CreatedAt time.Time `json:"createdAt" xml:"createdAt"`
+		UpdatedAt time.Time `json:"updatedAt" xml:"updatedAt"`
+		Name      string    `json:"name,omitempty" xml:"name,omitempty" validate:"required,max=64"`
+		Surname   string    `json:"surname,omitempty" xml:"surname,omitempty" validate:"max=64"`
+		Phone     string    `json:"phone,omitempty" xml:"phone,omitempty" validate:"e164"`
+		Address   string    `json:"address,omitempty" xml:"address,omitempty" validate:"max=256"`
 	}
 )
 
+// Revision returns the identity and the modification time of the user, validating its cached representations.
+func (e Entity) Revision() (uint, time.Time) {
+	return e.ID, e.UpdatedAt
+}
+
 func toEntity(_ context.Context, dbUser *models.User) (*Entity, error) {
 	entityUser := Entity{
 		ID:        dbUser.ID,
@@ -1,30 +1,41 @@
 package user
 
 import (
+	"context"
+
 	"gorm.io/gorm"
 
+	"solid-software.test-task/pkg/framework/password"
 	"solid-software.test-task/pkg/framework/store"
 	"solid-software.test-task/pkg/infra/db/models"
 )
 
 type (
-	// Service represents a user service with basic CRUD operations.
+	// Service represents a user service with basic CRUD operations and the login credentials management.
 	Service interface {
 		store.Repository[Entity]
+		// SetCredentials sets the username and the password of the user, replacing the previous ones.
+		SetCredentials(ctx context.Context, userID uint, credentials Credentials) error
+		// ChangePassword replaces the password of the user after verifying the current one.
+		ChangePassword(ctx context.Context, userID uint, change PasswordChange) error
+		// Authenticate returns the user the credentials belong to, or ErrInvalidCredentials.
+		Authenticate(ctx context.Context, credentials Credentials) (*Entity, error)
 	}
 
 	service struct {
 		*store.BaseStore[Entity, models.User]
+		hasher *password.Hasher
 	}
 )
 
 // NewUserService creates a new user service.
-// It takes a database connection interface *gorm.DB as a parameter
+// It takes a database connection interface *gorm.DB and the password hasher as parameters
 // and returns an instance of UserEntity Service.
-func NewUserService(db *gorm.DB) Service {
+func NewUserService(db *gorm.DB, hasher *password.Hasher) Service {
 	s := new(service)
 	s.BaseStore = store.New[Entity, models.User](db, toDBModel, toEntity)
 	s.DB = db
+	s.hasher = hasher
 
 	return s
 }
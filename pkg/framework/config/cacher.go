@@ -5,7 +5,6 @@ import (
 	"time"
 
 	"github.com/spf13/cast"
-	"github.com/spf13/viper"
 )
 
 type (
@@ -34,6 +33,8 @@ type (
 		GetTime(key string) time.Time
 		// GetDuration returns the value associated with the key as a duration.
 		GetDuration(key string) time.Duration
+		// GetSizeInBytes returns the value associated with the key as a size in bytes, e.g. "1MB" or "512kb".
+		GetSizeInBytes(key string) uint
 		// GetIntSlice returns the value associated with the key as a slice of int values.
 		GetIntSlice(key string) []int
 
@@ -41,6 +42,9 @@ type (
 		GetStringSlice(key string) []string
 		// GetStringMap returns the value associated with the key as a map of interfaces.
 		GetStringMap(key string) map[string]any
+
+		// OnChange registers the function called after a config reload has changed the value of the key.
+		OnChange(key string, fn func())
 	}
 
 	confImpl struct{}
@@ -68,57 +72,61 @@ func valuesCache[T any](key string, getter func(string) T, caster func(any) T) T
 }
 
 func (*confImpl) GetString(key string) string {
-	return valuesCache[string](key, viper.GetString, cast.ToString)
+	return valuesCache[string](key, viperInstance().GetString, cast.ToString)
 }
 
 func (*confImpl) GetBool(key string) bool {
-	return valuesCache(key, viper.GetBool, cast.ToBool)
+	return valuesCache(key, viperInstance().GetBool, cast.ToBool)
 }
 
 func (*confImpl) GetInt(key string) int {
-	return valuesCache(key, viper.GetInt, cast.ToInt)
+	return valuesCache(key, viperInstance().GetInt, cast.ToInt)
 }
 
 func (*confImpl) GetInt32(key string) int32 {
-	return valuesCache(key, viper.GetInt32, cast.ToInt32)
+	return valuesCache(key, viperInstance().GetInt32, cast.ToInt32)
 }
 
 func (*confImpl) GetInt64(key string) int64 {
-	return valuesCache(key, viper.GetInt64, cast.ToInt64)
+	return valuesCache(key, viperInstance().GetInt64, cast.ToInt64)
 }
 
 func (*confImpl) GetUint(key string) uint {
-	return valuesCache(key, viper.GetUint, cast.ToUint)
+	return valuesCache(key, viperInstance().GetUint, cast.ToUint)
 }
 
 func (*confImpl) GetUint32(key string) uint32 {
-	return valuesCache(key, viper.GetUint32, cast.ToUint32)
+	return valuesCache(key, viperInstance().GetUint32, cast.ToUint32)
 }
 
 func (*confImpl) GetUint64(key string) uint64 {
-	return valuesCache(key, viper.GetUint64, cast.ToUint64)
+	return valuesCache(key, viperInstance().GetUint64, cast.ToUint64)
 }
 
 func (*confImpl) GetFloat64(key string) float64 {
-	return valuesCache(key, viper.GetFloat64, cast.ToFloat64)
+	return valuesCache(key, viperInstance().GetFloat64, cast.ToFloat64)
 }
 
 func (*confImpl) GetTime(key string) time.Time {
-	return valuesCache(key, viper.GetTime, cast.ToTime)
+	return valuesCache(key, viperInstance().GetTime, cast.ToTime)
 }
 
 func (*confImpl) GetDuration(key string) time.Duration {
-	return valuesCache(key, viper.GetDuration, cast.ToDuration)
+	return valuesCache(key, viperInstance().GetDuration, cast.ToDuration)
+}
+
+func (*confImpl) GetSizeInBytes(key string) uint {
+	return valuesCache(key, viperInstance().GetSizeInBytes, cast.ToUint)
 }
 
 func (*confImpl) GetIntSlice(key string) []int {
-	return valuesCache(key, viper.GetIntSlice, cast.ToIntSlice)
+	return valuesCache(key, viperInstance().GetIntSlice, cast.ToIntSlice)
 }
 
 func (*confImpl) GetStringSlice(key string) []string {
-	return valuesCache(key, viper.GetStringSlice, cast.ToStringSlice)
+	return valuesCache(key, viperInstance().GetStringSlice, cast.ToStringSlice)
 }
 
 func (*confImpl) GetStringMap(key string) map[string]any {
-	return valuesCache(key, viper.GetStringMap, cast.ToStringMap)
+	return valuesCache(key, viperInstance().GetStringMap, cast.ToStringMap)
 }
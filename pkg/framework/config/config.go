@@ -2,36 +2,46 @@ package config
 
 import (
 	"fmt"
-	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/spf13/viper"
 )
 
 const (
-	// configName is the prefix of environment variables.
+	// configName is the name of the config files.
 	configName = "config"
-	envPrefix  = "sstt"
+	// envPrefix is the prefix of environment variables.
+	envPrefix = "sstt"
 )
 
 var (
 	_initConfigOnce sync.Once //nolint:gochecknoglobals
+	// _viper is the config read by the getters. The reloads replace it as a whole,
+	// so the readers never see a partially applied config.
+	_viper atomic.Pointer[viper.Viper] //nolint:gochecknoglobals
 )
 
-// Init initialize config.
-func Init() error {
+// Init initialize config from the sources selected by the options.
+// It fails if the config doesn't pass the checks of the typed sections, listing all the problems.
+func Init(options Options) error {
 	var err error
 
 	_initConfigOnce.Do(
 		func() {
-			viper.SetEnvPrefix(envPrefix)
-			viper.SetConfigName(configName)
-			viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
-			viper.SetConfigType("yaml")
-			viper.AddConfigPath(".")
-			viper.AutomaticEnv()
-			err = viper.ReadInConfig()
-			viper.WriteConfigAs("config.env")
+			_sources = newSources(options)
+
+			var (
+				v        *viper.Viper
+				sections *Sections
+			)
+
+			if v, sections, err = _sources.load(); err != nil {
+				return
+			}
+
+			_viper.Store(v)
+			_sections.Store(sections)
 		},
 	)
 
@@ -41,3 +51,13 @@ func Init() error {
 
 	return nil
 }
+
+// viperInstance returns the config loaded by Init or the last successful reload,
+// or the global viper instance before Init.
+func viperInstance() *viper.Viper {
+	if v := _viper.Load(); v != nil {
+		return v
+	}
+
+	return viper.GetViper()
+}
@@ -0,0 +1,377 @@
+package config
+
+import (
+	"time"
+)
+
+type (
+	// Sections is the typed config, decoded from the config file, the environment and the defaults.
+	// The JSON names of the fields are the config keys.
+	Sections struct {
+		Config      ConfigFile  `json:"config"`
+		Log         Log         `json:"log"`
+		Tracing     Tracing     `json:"tracing"`
+		OpenAPI     OpenAPI     `json:"openapi"`
+		Metrics     Metrics     `json:"metrics"`
+		Admin       Admin       `json:"admin"`
+		Maintenance Maintenance `json:"maintenance"`
+		Auth        Auth        `json:"auth"`
+		WebService  WebService  `json:"webService"`
+	}
+
+	// ConfigFile is the "config" section controlling the config file handling.
+	ConfigFile struct {
+		// Watch reloads the config file when it changes.
+		Watch bool `json:"watch"`
+	}
+
+	// Log is the "log" section.
+	Log struct {
+		Level      string   `json:"level"      validate:"required,pattern=(?i)^(debug|info|warn|error)$"`
+		Format     string   `json:"format"     validate:"required,pattern=^(json|text)$"`
+		RedactKeys []string `json:"redactKeys"`
+	}
+
+	// Tracing is the "tracing" section.
+	Tracing struct {
+		Enabled     bool    `json:"enabled"`
+		ServiceName string  `json:"serviceName" validate:"required"`
+		Exporter    string  `json:"exporter"    validate:"required,pattern=^(otlp|stdout|file)$"`
+		OTLP        OTLP    `json:"otlp"`
+		File        string  `json:"file"`
+		SampleRatio float64 `json:"sampleRatio" validate:"min=0,max=1"`
+	}
+
+	// OTLP is the "tracing.otlp" section.
+	OTLP struct {
+		Endpoint string `json:"endpoint"`
+		Insecure bool   `json:"insecure"`
+	}
+
+	// OpenAPI is the "openapi" section.
+	OpenAPI struct {
+		Enabled bool   `json:"enabled"`
+		Title   string `json:"title"   validate:"required"`
+		Version string `json:"version" validate:"required"`
+	}
+
+	// Metrics is the "metrics" section.
+	Metrics struct {
+		Enabled bool   `json:"enabled"`
+		Path    string `json:"path"    validate:"required,pattern=^/"`
+		Host    string `json:"host"`
+		Port    uint   `json:"port"    validate:"max=65535"`
+		// Token is the bearer credential of the metrics, required if they are served on the API listener.
+		Token string `json:"token"`
+	}
+
+	// Admin is the "admin" section.
+	Admin struct {
+		Enabled bool   `json:"enabled"`
+		Host    string `json:"host"`
+		Port    uint   `json:"port"    validate:"required,max=65535"`
+		Token   string `json:"token"`
+	}
+
+	// Maintenance is the "maintenance" section.
+	Maintenance struct {
+		Enabled    bool          `json:"enabled"`
+		Message    string        `json:"message"`
+		RetryAfter time.Duration `json:"retryAfter" validate:"min=0"`
+	}
+
+	// Auth is the "auth" section.
+	Auth struct {
+		// AnonymousTokens serves GET /token/generate issuing tokens to anyone, for the local development.
+		AnonymousTokens bool `json:"anonymousTokens"`
+		// Permissions are granted to the tokens issued at login, e.g. "users".
+		Permissions []string `json:"permissions"`
+		// Admins are the usernames whose logins are granted AdminPermissions instead of Permissions.
+		Admins           []string     `json:"admins"`
+		AdminPermissions []string     `json:"adminPermissions"`
+		Password         Password     `json:"password"`
+		RefreshToken     RefreshToken `json:"refreshToken"`
+	}
+
+	// RefreshToken is the "auth.refreshToken" section.
+	RefreshToken struct {
+		// TTL is the lifetime of a refresh token; each rotation issues a token with the full lifetime.
+		TTL time.Duration `json:"ttl" validate:"min=1"`
+	}
+
+	// Password is the "auth.password" section: the password policy and the hashing parameters.
+	Password struct {
+		// Algorithm hashes the new passwords; the hashes of the other algorithm are verified and rehashed at login.
+		Algorithm  string   `json:"algorithm"  validate:"required,pattern=^(argon2id|bcrypt)$"`
+		MinLength  int      `json:"minLength"  validate:"min=8"`
+		MaxLength  int      `json:"maxLength"  validate:"min=8,max=1024"`
+		MinClasses int      `json:"minClasses" validate:"min=0,max=4"`
+		Argon2id   Argon2id `json:"argon2id"`
+		Bcrypt     Bcrypt   `json:"bcrypt"`
+	}
+
+	// Argon2id is the "auth.password.argon2id" section.
+	Argon2id struct {
+		Memory      Size   `json:"memory"      validate:"min=8192"`
+		Iterations  uint32 `json:"iterations"  validate:"min=1"`
+		Parallelism uint8  `json:"parallelism" validate:"min=1"`
+	}
+
+	// Bcrypt is the "auth.password.bcrypt" section.
+	Bcrypt struct {
+		Cost int `json:"cost" validate:"min=4,max=31"`
+	}
+
+	// WebService is the "webService" section.
+	WebService struct {
+		Host            string              `json:"host"`
+		Port            uint                `json:"port"            validate:"required,max=65535"`
+		ShutdownTimeout time.Duration       `json:"shutdownTimeout" validate:"min=0"`
+		H2C             bool                `json:"h2c"`
+		Listeners       map[string]Listener `json:"listeners"`
+		TLS             TLS                 `json:"tls"`
+		JWT             JWT                 `json:"jwt"`
+		CORS            CORS                `json:"cors"`
+		Versions        map[string]Version  `json:"versions"`
+		RequestTimeout  RequestTimeout      `json:"requestTimeout"`
+		RequestBody     RequestBody         `json:"requestBody"`
+		Compression     Compression         `json:"compression"`
+		CacheControl    CacheControl        `json:"cacheControl"`
+		Idempotency     Idempotency         `json:"idempotency"`
+		RateLimit       RateLimit           `json:"rateLimit"`
+	}
+
+	// Listener is an entry of the "webService.listeners" section.
+	Listener struct {
+		Network    string `json:"network"    validate:"pattern=^(tcp|unix)$"`
+		Address    string `json:"address"    validate:"required"`
+		SocketMode string `json:"socketMode"`
+		TLS        bool   `json:"tls"`
+		H2C        bool   `json:"h2c"`
+		// TrustForwardedFor takes the client address from the X-Forwarded-For header of the trusted proxy.
+		TrustForwardedFor bool `json:"trustForwardedFor"`
+	}
+
+	// TLS is the "webService.tls" section.
+	TLS struct {
+		Enabled      bool         `json:"enabled"`
+		CertFile     string       `json:"certFile"`
+		KeyFile      string       `json:"keyFile"`
+		MinVersion   string       `json:"minVersion"   validate:"pattern=^1\\.[23]$"`
+		CipherSuites []string     `json:"cipherSuites"`
+		ClientAuth   ClientAuth   `json:"clientAuth"`
+		RedirectHTTP RedirectHTTP `json:"redirectHTTP"`
+	}
+
+	// ClientAuth is the "webService.tls.clientAuth" section.
+	ClientAuth struct {
+		CAFile string `json:"caFile"`
+		Mode   string `json:"mode"   validate:"pattern=^(require|optional)$"`
+	}
+
+	// RedirectHTTP is the "webService.tls.redirectHTTP" section.
+	RedirectHTTP struct {
+		Enabled bool `json:"enabled"`
+		Port    uint `json:"port"    validate:"max=65535"`
+	}
+
+	// JWT is the "webService.jwt" section.
+	JWT struct {
+		Secret                       string `json:"secret"                       validate:"required,min=16"`
+		TokenExpirationTimeInMinutes int64  `json:"tokenExpirationTimeInMinutes" validate:"min=1"`
+		// Permissions are granted to the anonymous tokens of GET /token/generate.
+		Permissions []string `json:"permissions"`
+	}
+
+	// CORS is the "webService.cors" section.
+	CORS struct {
+		Enabled          bool          `json:"enabled"`
+		AllowedOrigins   []string      `json:"allowedOrigins"`
+		AllowedMethods   []string      `json:"allowedMethods"`
+		AllowedHeaders   []string      `json:"allowedHeaders"`
+		ExposedHeaders   []string      `json:"exposedHeaders"`
+		AllowCredentials bool          `json:"allowCredentials"`
+		MaxAge           time.Duration `json:"maxAge"           validate:"min=0"`
+	}
+
+	// Version is an entry of the "webService.versions" section.
+	Version struct {
+		Deprecation time.Time `json:"deprecation"`
+		Sunset      time.Time `json:"sunset"`
+		Link        string    `json:"link"`
+	}
+
+	// RequestTimeout is the "webService.requestTimeout" section.
+	RequestTimeout struct {
+		Default time.Duration            `json:"default" validate:"min=0"`
+		Routes  map[string]time.Duration `json:"routes"`
+	}
+
+	// RequestBody is the "webService.requestBody" section.
+	RequestBody struct {
+		MaxSize             Size            `json:"maxSize"`
+		MaxDecompressedSize Size            `json:"maxDecompressedSize"`
+		Routes              map[string]Size `json:"routes"`
+	}
+
+	// Compression is the "webService.compression" section.
+	Compression struct {
+		Enabled   bool     `json:"enabled"`
+		MinSize   Size     `json:"minSize"`
+		Encodings []string `json:"encodings"`
+		Level     int      `json:"level"`
+	}
+
+	// CacheControl is the "webService.cacheControl" section.
+	CacheControl struct {
+		Default string            `json:"default"`
+		Routes  map[string]string `json:"routes"`
+	}
+
+	// Idempotency is the "webService.idempotency" section.
+	Idempotency struct {
+		Enabled      bool          `json:"enabled"`
+		TTL          time.Duration `json:"ttl"          validate:"min=0"`
+		LockTimeout  time.Duration `json:"lockTimeout"  validate:"min=0"`
+		MaxKeyLength int           `json:"maxKeyLength" validate:"min=1"`
+		// Secret keys the HMAC fingerprints of the request payloads kept with the keys.
+		Secret string `json:"secret"`
+	}
+
+	// RateLimit is the "webService.rateLimit" section.
+	RateLimit struct {
+		Enabled           bool                      `json:"enabled"`
+		RequestsPerMinute float64                   `json:"requestsPerMinute" validate:"min=0"`
+		Burst             int                       `json:"burst"             validate:"min=0"`
+		Routes            map[string]RateLimitRoute `json:"routes"`
+	}
+
+	// RateLimitRoute is an entry of the "webService.rateLimit.routes" section.
+	RateLimitRoute struct {
+		RequestsPerMinute float64 `json:"requestsPerMinute" validate:"min=0"`
+		Burst             int     `json:"burst"             validate:"min=0"`
+	}
+
+	// Size is a size in bytes, configured as a number or with the kb, mb and gb suffixes, e.g. "512kb".
+	Size uint
+)
+
+// _defaults are the values of the keys missing in the config file and the environment.
+// Registering them also makes every key overridable by its environment variable.
+var _defaults = map[string]any{ //nolint:gochecknoglobals
+	"config.watch": false,
+
+	"log.level":      "info",
+	"log.format":     "json",
+	"log.redactKeys": []string{"password", "token", "authorization", "secret"},
+
+	"tracing.enabled":       false,
+	"tracing.serviceName":   "api-server",
+	"tracing.exporter":      "otlp",
+	"tracing.otlp.endpoint": "localhost:4318",
+	"tracing.otlp.insecure": true,
+	"tracing.file":          "./traces.json",
+	"tracing.sampleRatio":   1,
+
+	"openapi.enabled": true,
+	"openapi.title":   "api-server",
+	"openapi.version": "1.0.0",
+
+	"metrics.enabled": true,
+	"metrics.path":    "/metrics",
+	"metrics.host":    "127.0.0.1",
+	"metrics.port":    9090,
+	"metrics.token":   "",
+
+	"admin.enabled": false,
+	"admin.host":    "127.0.0.1",
+	"admin.port":    9091,
+	"admin.token":   "",
+
+	"maintenance.enabled":    false,
+	"maintenance.message":    "",
+	"maintenance.retryAfter": "5m",
+
+	"auth.anonymousTokens":               false,
+	"auth.permissions":                   []string{"users"},
+	"auth.admins":                        []string{},
+	"auth.adminPermissions":              []string{"users", "credentials", "routes"},
+	"auth.password.algorithm":            "argon2id",
+	"auth.password.minLength":            12,
+	"auth.password.maxLength":            64,
+	"auth.password.minClasses":           2,
+	"auth.password.argon2id.memory":      "64mb",
+	"auth.password.argon2id.iterations":  3,
+	"auth.password.argon2id.parallelism": 2,
+	"auth.password.bcrypt.cost":          12,
+	"auth.refreshToken.ttl":              "720h",
+
+	"webService.host":            "",
+	"webService.port":            80,
+	"webService.shutdownTimeout": "15s",
+	"webService.h2c":             false,
+
+	"webService.tls.enabled":              false,
+	"webService.tls.certFile":             "",
+	"webService.tls.keyFile":              "",
+	"webService.tls.minVersion":           "1.2",
+	"webService.tls.cipherSuites":         []string{},
+	"webService.tls.clientAuth.caFile":    "",
+	"webService.tls.clientAuth.mode":      "require",
+	"webService.tls.redirectHTTP.enabled": false,
+	"webService.tls.redirectHTTP.port":    8080,
+
+	"webService.jwt.secret":                       "",
+	"webService.jwt.tokenExpirationTimeInMinutes": 60,
+	"webService.jwt.permissions":                  []string{},
+
+	"webService.cors.enabled":          false,
+	"webService.cors.allowedOrigins":   []string{},
+	"webService.cors.allowedMethods":   []string{},
+	"webService.cors.allowedHeaders":   []string{},
+	"webService.cors.exposedHeaders":   []string{},
+	"webService.cors.allowCredentials": false,
+	"webService.cors.maxAge":           "10m",
+
+	"webService.requestTimeout.default":          "30s",
+	"webService.requestBody.maxSize":             "1mb",
+	"webService.requestBody.maxDecompressedSize": "10mb",
+
+	"webService.compression.enabled":   true,
+	"webService.compression.minSize":   "1kb",
+	"webService.compression.encodings": []string{"br", "gzip", "deflate"},
+	"webService.compression.level":     -1,
+
+	"webService.cacheControl.default": "private, no-cache",
+
+	"webService.idempotency.enabled":      true,
+	"webService.idempotency.ttl":          "24h",
+	"webService.idempotency.lockTimeout":  "1m",
+	"webService.idempotency.maxKeyLength": 255,
+	"webService.idempotency.secret":       "",
+
+	"webService.rateLimit.enabled":           true,
+	"webService.rateLimit.requestsPerMinute": 120,
+	"webService.rateLimit.burst":             30,
+}
+
+// NewPasswordSection returns the "auth.password" section following the config reloads.
+func NewPasswordSection(cfg Config) *Watched[Password] {
+	return NewWatchedSection(cfg, "auth.password", func(sections *Sections) Password {
+		return sections.Auth.Password
+	})
+}
+
+// NewRefreshTokenSection returns the "auth.refreshToken" section following the config reloads.
+func NewRefreshTokenSection(cfg Config) *Watched[RefreshToken] {
+	return NewWatchedSection(cfg, "auth.refreshToken", func(sections *Sections) RefreshToken {
+		return sections.Auth.RefreshToken
+	})
+}
+
+// NewJWTSection returns the "webService.jwt" section following the config reloads.
+func NewJWTSection(cfg Config) *Watched[JWT] {
+	return NewWatchedSection(cfg, "webService.jwt", func(sections *Sections) JWT {
+		return sections.WebService.JWT
+	})
+}
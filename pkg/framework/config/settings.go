@@ -0,0 +1,85 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/spf13/cast"
+)
+
+const (
+	// RedactedValue replaces the secret values in the config dumps.
+	RedactedValue = "[REDACTED]"
+)
+
+var (
+	// _secretKeySuffixes mark the config keys holding secrets, e.g. "webService.jwt.secret" or "admin.token".
+	_secretKeySuffixes = []string{"secret", "password", "token", "credential", "privatekey", "apikey"} //nolint:gochecknoglobals
+)
+
+// AllSettings returns the effective config, merged from the config file, the environment and the defaults.
+func AllSettings() map[string]any {
+	return viperInstance().AllSettings()
+}
+
+// RedactedSettings returns the effective config with the values of the secret keys replaced.
+func RedactedSettings() map[string]any {
+	return redact(AllSettings())
+}
+
+// IsSecretKey reports whether the config key holds a secret.
+func IsSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, suffix := range _secretKeySuffixes {
+		if strings.HasSuffix(key, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DropCache drops the cached values, so they are read from the config again.
+func DropCache() {
+	_cache.Range(func(key, _ any) bool {
+		_cache.Delete(key)
+
+		return true
+	})
+}
+
+func redact(settings map[string]any) map[string]any {
+	redacted := make(map[string]any, len(settings))
+
+	for key, value := range settings {
+		switch nested, ok := value.(map[string]any); {
+		case ok:
+			value = redact(nested)
+		case IsSecretKey(key) && value != nil && value != "":
+			value = RedactedValue
+		}
+
+		redacted[key] = value
+	}
+
+	return redacted
+}
+
+// SizeInBytes converts the config value to a size in bytes the way GetSizeInBytes does,
+// e.g. for the values of the maps returned by GetStringMap. It accepts the kb, mb and gb suffixes case-insensitively.
+func SizeInBytes(value any) uint {
+	size := strings.ToLower(strings.TrimSpace(cast.ToString(value)))
+	multiplier := uint(1)
+
+	for _, unit := range []struct {
+		suffix     string
+		multiplier uint
+	}{{"gb", 1 << 30}, {"mb", 1 << 20}, {"kb", 1 << 10}, {"g", 1 << 30}, {"m", 1 << 20}, {"k", 1 << 10}, {"b", 1}} {
+		if trimmed, ok := strings.CutSuffix(size, unit.suffix); ok {
+			size, multiplier = strings.TrimSpace(trimmed), unit.multiplier
+
+			break
+		}
+	}
+
+	return cast.ToUint(size) * multiplier
+}
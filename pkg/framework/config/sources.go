@@ -0,0 +1,238 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// defaultFile is the base config file read when no path is given.
+	defaultFile = configName + ".yaml"
+	// fileSuffix marks the environment variables holding the path of a file with the value of a key,
+	// e.g. SSTT_WEBSERVICE_JWT_SECRET_FILE=/run/secrets/jwt-secret.
+	fileSuffix = "_FILE"
+)
+
+type (
+	// Options select the config sources. From the lowest to the highest precedence they are:
+	// the defaults, the base file, the environment profile, the environment variables and the files
+	// they point to, and the overrides.
+	Options struct {
+		// File is the path of the base config file, "./config.yaml" if empty.
+		File string
+		// Env is the name of the environment profile, e.g. "prod", layered over the base file
+		// from the file named after it next to the base file, e.g. "./config.prod.yaml". Defaults to SSTT_ENV.
+		Env string
+		// Overrides are the values keyed by config key, e.g. set by the command-line flags. They override every other source.
+		Overrides map[string]string
+	}
+
+	sources struct {
+		file      string
+		profile   string
+		overrides map[string]string
+	}
+
+	// layers are the contents of the sources read at once, so the config applied is the one checked.
+	layers struct {
+		base    []byte
+		profile []byte
+		// secrets are the contents of the files pointed to by the *_FILE environment variables, keyed by path.
+		secrets   map[string]string
+		overrides map[string]string
+	}
+)
+
+var (
+	_sources sources //nolint:gochecknoglobals
+)
+
+func newSources(options Options) sources {
+	file := options.File
+	if file == "" {
+		file = defaultFile
+	}
+
+	env := options.Env
+	if env == "" {
+		env = os.Getenv(envVariable("env"))
+	}
+
+	var profile string
+
+	if env != "" {
+		extension := filepath.Ext(file)
+		profile = strings.TrimSuffix(file, extension) + "." + env + extension
+	}
+
+	return sources{file: file, profile: profile, overrides: options.Overrides}
+}
+
+// load builds a new viper instance from the sources, then decodes and checks its config.
+func (s sources) load() (*viper.Viper, *Sections, error) {
+	read, err := s.read()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	v := viper.New()
+	s.configure(v)
+
+	if err = read.apply(v); err != nil {
+		return nil, nil, err
+	}
+
+	sections, err := decode(v)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return v, sections, nil
+}
+
+// read reads the base file and the environment profile.
+func (s sources) read() (*layers, error) {
+	base, err := os.ReadFile(s.file)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", s.file, err)
+	}
+
+	read := &layers{base: base, secrets: map[string]string{}, overrides: s.overrides}
+
+	if s.profile != "" {
+		if read.profile, err = os.ReadFile(s.profile); err != nil {
+			return nil, fmt.Errorf("read profile: %w", err)
+		}
+	}
+
+	return read, nil
+}
+
+// apply replaces the config of the viper instance with the base file, merges the environment profile into it,
+// then applies the values of the files pointed to by the *_FILE environment variables and the overrides.
+// The files are read by the first apply, so the later ones apply the same values.
+func (l *layers) apply(v *viper.Viper) error {
+	if err := v.ReadConfig(bytes.NewReader(l.base)); err != nil {
+		return fmt.Errorf("parse %s: %w", v.ConfigFileUsed(), err)
+	}
+
+	if l.profile != nil {
+		if err := v.MergeConfig(bytes.NewReader(l.profile)); err != nil {
+			return fmt.Errorf("merge profile: %w", err)
+		}
+	}
+
+	for _, key := range v.AllKeys() {
+		variable := envVariable(key) + fileSuffix
+
+		path := os.Getenv(variable)
+		if path == "" {
+			continue
+		}
+
+		content, ok := l.secrets[path]
+		if !ok {
+			read, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("read %s: %w", variable, err)
+			}
+
+			// the files usually end with a newline which is not a part of the value.
+			content = strings.TrimRight(string(read), "\r\n")
+			l.secrets[path] = content
+		}
+
+		v.Set(key, content)
+	}
+
+	for key, value := range l.overrides {
+		v.Set(key, parseValue(key, value))
+	}
+
+	return nil
+}
+
+// configure sets the viper instance up to read the sources: the defaults, the environment variables and the file type.
+func (s sources) configure(v *viper.Viper) {
+	v.SetEnvPrefix(envPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.SetConfigFile(s.file)
+	v.AutomaticEnv()
+
+	for key, value := range _defaults {
+		v.SetDefault(key, value)
+	}
+}
+
+// files returns the paths of the config files to watch.
+func (s sources) files() []string {
+	if s.profile == "" {
+		return []string{s.file}
+	}
+
+	return []string{s.file, s.profile}
+}
+
+// parseValue parses the override of the key. The values of the string keys of the typed sections are kept
+// as given, so a secret like "0123" keeps its leading zero. The values of the other keys are parsed as YAML,
+// so "8080" is a number and "[a, b]" is a list; a quoted value, e.g. '"0123"', stays a string.
+// A value which isn't valid YAML is kept as a string.
+func parseValue(key, value string) any {
+	if isStringKey(key) {
+		return value
+	}
+
+	var parsed any
+	if err := yaml.Unmarshal([]byte(value), &parsed); err != nil || parsed == nil {
+		return value
+	}
+
+	return parsed
+}
+
+// isStringKey reports whether the key is a string value of the typed sections, looked up by their json tags.
+// The keys under the maps, e.g. "webService.listeners.public.host", are looked up in the map value type.
+func isStringKey(key string) bool {
+	target := reflect.TypeOf(Sections{})
+
+	for _, name := range strings.Split(key, ".") {
+		switch target.Kind() { //nolint:exhaustive
+		case reflect.Map:
+			target = target.Elem()
+		case reflect.Struct:
+			field, ok := fieldByTag(target, name)
+			if !ok {
+				return false
+			}
+
+			target = field.Type
+		default:
+			return false
+		}
+	}
+
+	return target.Kind() == reflect.String
+}
+
+func fieldByTag(target reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < target.NumField(); i++ {
+		field := target.Field(i)
+		if tag, _, _ := strings.Cut(field.Tag.Get("json"), ","); strings.EqualFold(tag, name) {
+			return field, true
+		}
+	}
+
+	return reflect.StructField{}, false
+}
+
+// envVariable returns the name of the environment variable of the key, e.g. SSTT_WEBSERVICE_PORT.
+func envVariable(key string) string {
+	return strings.ToUpper(envPrefix + "_" + strings.ReplaceAll(key, ".", "_"))
+}
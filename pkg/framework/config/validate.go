@@ -0,0 +1,238 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/cast"
+	"github.com/spf13/viper"
+
+	"solid-software.test-task/pkg/framework/validation"
+)
+
+const (
+	minCompressionLevel = -1
+	maxCompressionLevel = 11
+	// minSecretLength is the length of the HMAC secrets required when their feature is enabled.
+	minSecretLength = 16
+	// maxBcryptPasswordLength is the length of the passwords bcrypt accepts, in bytes.
+	maxBcryptPasswordLength = 72
+)
+
+type (
+	// InvalidError is returned when the config can't be decoded into the typed sections or fails their checks.
+	// It lists all the problems.
+	InvalidError struct {
+		Problems []string
+	}
+)
+
+var (
+	// ErrInvalid is matched by the InvalidError.
+	ErrInvalid = errors.New("invalid config")
+
+	_sizePattern = regexp.MustCompile(`^(?i)\d+\s*([kmg]b?|b)?$`) //nolint:gochecknoglobals
+	_sizeType    = reflect.TypeOf(Size(0))                        //nolint:gochecknoglobals
+	_timeType    = reflect.TypeOf(time.Time{})                    //nolint:gochecknoglobals
+	_encodings   = []string{"br", "gzip", "deflate"}              //nolint:gochecknoglobals
+	_sections    atomic.Pointer[Sections]                         //nolint:gochecknoglobals
+)
+
+// Error returns the problems, one per line.
+func (e *InvalidError) Error() string {
+	return ErrInvalid.Error() + ":\n  " + strings.Join(e.Problems, "\n  ")
+}
+
+// Is reports whether the target is ErrInvalid.
+func (e *InvalidError) Is(target error) bool {
+	return target == ErrInvalid //nolint:errorlint
+}
+
+// Current returns the typed sections of the config loaded by Init or the last successful reload.
+// They are zero before Init.
+func Current() *Sections {
+	if sections := _sections.Load(); sections != nil {
+		return sections
+	}
+
+	return &Sections{}
+}
+
+// Validate decodes the current config into the typed sections and checks them without applying them.
+func Validate() (*Sections, error) {
+	return decode(viperInstance())
+}
+
+// decode decodes the config of the viper instance into the typed sections. The unknown keys, the values
+// of a wrong type and the sections failing their checks are reported together in an InvalidError.
+func decode(v *viper.Viper) (*Sections, error) {
+	var (
+		sections Sections
+		problems []string
+	)
+
+	err := v.Unmarshal(&sections, func(decoderConfig *mapstructure.DecoderConfig) {
+		decoderConfig.TagName = "json"
+		decoderConfig.ErrorUnused = true
+		decoderConfig.DecodeHook = mapstructure.ComposeDecodeHookFunc(
+			decoderConfig.DecodeHook,
+			decodeSize,
+			decodeTime,
+		)
+	})
+	if err != nil {
+		var decodeErr *mapstructure.Error
+		if !errors.As(err, &decodeErr) {
+			return nil, fmt.Errorf("decode config: %w", err)
+		}
+
+		problems = append(problems, decodeErr.Errors...)
+	}
+
+	problems = append(problems, sections.check()...)
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+
+		return nil, &InvalidError{Problems: problems}
+	}
+
+	return &sections, nil
+}
+
+// check validates the sections against the rules of their validate tags and the rules spanning several keys.
+func (s *Sections) check() []string {
+	problems := checkRules("", s)
+
+	for name, listener := range s.WebService.Listeners {
+		problems = append(problems, checkRules("webService.listeners."+name, listener)...)
+	}
+
+	for path, limit := range s.WebService.RateLimit.Routes {
+		problems = append(problems, checkRules("webService.rateLimit.routes."+path, limit)...)
+	}
+
+	if s.Metrics.Enabled && s.Metrics.Port == 0 && s.Metrics.Token == "" {
+		problems = append(problems, "metrics.token: is required when the metrics are served on the API listener")
+	}
+
+	if s.Admin.Enabled && s.Admin.Token == "" {
+		problems = append(problems, "admin.token: is required when the admin listener is enabled")
+	}
+
+	if idempotency := s.WebService.Idempotency; idempotency.Enabled && len(idempotency.Secret) < minSecretLength {
+		problems = append(problems, fmt.Sprintf(
+			"webService.idempotency.secret: must be at least %d characters long when idempotency is enabled",
+			minSecretLength,
+		))
+	}
+
+	if s.WebService.TLS.Enabled {
+		if s.WebService.TLS.CertFile == "" {
+			problems = append(problems, "webService.tls.certFile: is required when TLS is enabled")
+		}
+
+		if s.WebService.TLS.KeyFile == "" {
+			problems = append(problems, "webService.tls.keyFile: is required when TLS is enabled")
+		}
+	}
+
+	if s.Tracing.Enabled && s.Tracing.Exporter == "otlp" && s.Tracing.OTLP.Endpoint == "" {
+		problems = append(problems, "tracing.otlp.endpoint: is required by the otlp exporter")
+	}
+
+	if policy := s.Auth.Password; policy.MaxLength < policy.MinLength {
+		problems = append(problems, "auth.password.maxLength: must not be less than auth.password.minLength")
+	} else if policy.Algorithm == "bcrypt" && policy.MaxLength > maxBcryptPasswordLength {
+		problems = append(problems, fmt.Sprintf(
+			"auth.password.maxLength: must be at most %d, as bcrypt ignores the longer passwords", maxBcryptPasswordLength,
+		))
+	}
+
+	accessTokenTTL := time.Duration(s.WebService.JWT.TokenExpirationTimeInMinutes) * time.Minute
+	if s.Auth.RefreshToken.TTL <= accessTokenTTL {
+		problems = append(problems,
+			"auth.refreshToken.ttl: must be longer than webService.jwt.tokenExpirationTimeInMinutes")
+	}
+
+	if level := s.WebService.Compression.Level; level < minCompressionLevel || level > maxCompressionLevel {
+		problems = append(problems, fmt.Sprintf(
+			"webService.compression.level: must be between %d and %d", minCompressionLevel, maxCompressionLevel,
+		))
+	}
+
+	for _, encoding := range s.WebService.Compression.Encodings {
+		if !slices.Contains(_encodings, strings.ToLower(encoding)) {
+			problems = append(problems, fmt.Sprintf(
+				"webService.compression.encodings: unsupported encoding %q, must be one of %s",
+				encoding, strings.Join(_encodings, ", "),
+			))
+		}
+	}
+
+	return problems
+}
+
+// checkRules validates the value and reports the failures by their config keys under the prefix.
+func checkRules(prefix string, value any) []string {
+	var invalid *validation.Error
+	if !errors.As(validation.Validate(value), &invalid) {
+		return nil
+	}
+
+	problems := make([]string, 0, len(invalid.InvalidParams))
+	for _, param := range invalid.InvalidParams {
+		key := strings.ReplaceAll(strings.TrimPrefix(param.Pointer, "/"), "/", ".")
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		problems = append(problems, key+": "+param.Reason)
+	}
+
+	return problems
+}
+
+// decodeSize decodes the sizes with the kb, mb and gb suffixes, rejecting the malformed ones.
+func decodeSize(_ reflect.Type, to reflect.Type, data any) (any, error) {
+	if to != _sizeType {
+		return data, nil
+	}
+
+	text := strings.TrimSpace(cast.ToString(data))
+	if text == "" {
+		return Size(0), nil
+	}
+
+	if !_sizePattern.MatchString(text) {
+		return nil, fmt.Errorf("invalid size %q, e.g. 512kb or 1mb expected", text) //nolint:goerr113
+	}
+
+	return Size(SizeInBytes(text)), nil
+}
+
+// decodeTime decodes the times in the formats accepted by cast, e.g. RFC 3339 or "2006-01-02".
+func decodeTime(_ reflect.Type, to reflect.Type, data any) (any, error) {
+	if to != _timeType {
+		return data, nil
+	}
+
+	if data == nil || data == "" {
+		return time.Time{}, nil
+	}
+
+	parsed, err := cast.ToTimeE(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse time: %w", err)
+	}
+
+	return parsed, nil
+}
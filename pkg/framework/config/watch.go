@@ -0,0 +1,278 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+type (
+	subscription struct {
+		key string
+		fn  func()
+		// last is the value of the key the subscriber has seen.
+		last any
+	}
+
+	// watchedFiles maps the watched config files to the files they resolve to through the symlinks.
+	watchedFiles map[string]string
+
+	// Watched holds a value built from the config, rebuilt when the config section changes.
+	Watched[T any] struct {
+		value atomic.Pointer[T]
+
+		mu sync.Mutex
+		// derived rebuild the values mapped from this one.
+		derived []func(T)
+	}
+)
+
+var (
+	_subscriptionsMu sync.Mutex      //nolint:gochecknoglobals
+	_subscriptions   []*subscription //nolint:gochecknoglobals
+	// _reloadMu serializes the reloads triggered by the file watcher and the signals.
+	_reloadMu sync.Mutex //nolint:gochecknoglobals
+)
+
+// OnChange registers the function called after a reload has changed the value of the key.
+// The key may be a section, e.g. "webService.cors", to be notified of a change of any of its values.
+func (*confImpl) OnChange(key string, fn func()) {
+	_subscriptionsMu.Lock()
+	defer _subscriptionsMu.Unlock()
+
+	key = strings.ToLower(key)
+	_subscriptions = append(_subscriptions, &subscription{key: key, fn: fn, last: snapshot(key)})
+}
+
+// NewWatched builds the value from the config and rebuilds it whenever the key changes.
+func NewWatched[T any](cfg Config, key string, build func(Config) T) *Watched[T] {
+	watched := &Watched[T]{}
+	watched.store(build(cfg))
+
+	cfg.OnChange(key, func() {
+		watched.store(build(cfg))
+	})
+
+	return watched
+}
+
+// NewWatchedSection returns the typed section picked from the current sections, updated whenever the key changes.
+func NewWatchedSection[T any](cfg Config, key string, pick func(*Sections) T) *Watched[T] {
+	return NewWatched(cfg, key, func(Config) T {
+		return pick(Current())
+	})
+}
+
+// Map returns the value built from the watched one, rebuilt whenever the watched value changes.
+func Map[T, U any](watched *Watched[T], build func(T) U) *Watched[U] {
+	mapped := &Watched[U]{}
+
+	watched.mu.Lock()
+	defer watched.mu.Unlock()
+
+	mapped.store(build(watched.Load()))
+	watched.derived = append(watched.derived, func(value T) {
+		mapped.store(build(value))
+	})
+
+	return mapped
+}
+
+// Load returns the value built from the current config.
+func (w *Watched[T]) Load() T {
+	return *w.value.Load()
+}
+
+func (w *Watched[T]) store(value T) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.value.Store(&value)
+
+	for _, rebuild := range w.derived {
+		rebuild(value)
+	}
+}
+
+// Reload reads the config files again into a new config, checks it and replaces the current one if it is valid,
+// then drops the cached values and notifies the subscribers of the changed keys.
+func Reload() error {
+	_reloadMu.Lock()
+	defer _reloadMu.Unlock()
+
+	v, sections, err := _sources.load()
+	if err != nil {
+		return fmt.Errorf("reload config: %w", err)
+	}
+
+	_viper.Store(v)
+	_sections.Store(sections)
+	DropCache()
+
+	_subscriptionsMu.Lock()
+	subscriptions := append([]*subscription(nil), _subscriptions...)
+	_subscriptionsMu.Unlock()
+
+	// the values are compared with the ones seen by the subscribers.
+	for _, s := range subscriptions {
+		if current := snapshot(s.key); !reflect.DeepEqual(s.last, current) {
+			s.last = current
+			s.fn()
+		}
+	}
+
+	return nil
+}
+
+// Watch reloads the config when the config files change, if "config.watch" is set, or on SIGHUP until the context is done.
+// The directories of the files are watched instead of the files, so atomic replacements are noticed as well.
+// The events of the other files of the directories are ignored, unless they re-point the symlinks
+// the config files resolve through (e.g. Kubernetes config map volume updates).
+func Watch(ctx context.Context) {
+	var (
+		events <-chan fsnotify.Event
+		errs   <-chan error
+	)
+
+	files := newWatchedFiles(_sources.files())
+
+	if viperInstance().GetBool("config.watch") {
+		watcher, err := watchFiles(_sources.files())
+		if err != nil {
+			slog.Default().Error("config watcher", slog.Any("error", err))
+		} else {
+			defer watcher.Close()
+
+			events, errs = watcher.Events, watcher.Errors
+		}
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+
+	defer signal.Stop(signals)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				events = nil
+
+				continue
+			}
+
+			if !files.changed(event) {
+				continue
+			}
+
+			logReload(Reload(), slog.String("file", event.Name))
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+
+				continue
+			}
+
+			slog.Default().Error("config watcher", slog.Any("error", err))
+		case <-signals:
+			logReload(Reload())
+		}
+	}
+}
+
+func watchFiles(files []string) (*fsnotify.Watcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("create watcher: %w", err)
+	}
+
+	for _, file := range files {
+		if err = watcher.Add(filepath.Dir(file)); err != nil {
+			watcher.Close()
+
+			return nil, fmt.Errorf("watch %s: %w", filepath.Dir(file), err)
+		}
+	}
+
+	return watcher, nil
+}
+
+func newWatchedFiles(files []string) watchedFiles {
+	targets := make(watchedFiles, len(files))
+
+	for _, file := range files {
+		file = filepath.Clean(file)
+		targets[file] = resolve(file)
+	}
+
+	return targets
+}
+
+// changed reports whether the event changes a config file: writes or creates the file itself,
+// or makes its path resolve to another file, e.g. by replacing a symlinked directory.
+func (w watchedFiles) changed(event fsnotify.Event) bool {
+	name := filepath.Clean(event.Name)
+	changed := false
+
+	for file, target := range w {
+		if current := resolve(file); current != target {
+			w[file] = current
+			// a removed file is not a change to apply, its recreation is.
+			changed = changed || current != ""
+		}
+
+		if name == file && event.Op&(fsnotify.Write|fsnotify.Create) != 0 {
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+// resolve returns the file the path resolves to through the symlinks, or "" if it doesn't exist.
+func resolve(path string) string {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return ""
+	}
+
+	return resolved
+}
+
+func logReload(err error, attrs ...any) {
+	if err != nil {
+		slog.Default().Error("reload config", slog.Any("error", err))
+
+		return
+	}
+
+	slog.Default().Info("config reloaded", attrs...)
+}
+
+// snapshot returns the values of the key and of the keys under it. The values are collected key by key,
+// as viper returns a section partially when some of its keys are overridden.
+func snapshot(key string) map[string]any {
+	values := map[string]any{}
+
+	v := viperInstance()
+
+	for _, k := range v.AllKeys() {
+		if k == key || strings.HasPrefix(k, key+".") {
+			values[k] = v.Get(k)
+		}
+	}
+
+	return values
+}
@@ -1,5 +1,9 @@
 package ctxutils
 
+import (
+	"context"
+)
+
 type (
 	appContextKey string
 )
@@ -9,4 +13,58 @@ const (
 	AppContextKey appContextKey = "appContext"
 	// UsernameContextKey is the key for the username context.
 	UsernameContextKey appContextKey = "username"
+	// SubjectContextKey is the key for the ID of the authenticated user.
+	SubjectContextKey appContextKey = "subject"
+	// RequestIDContextKey is the key for the request ID.
+	RequestIDContextKey appContextKey = "requestID"
+	// PermissionsContextKey is the key for the permissions granted to the authenticated client.
+	PermissionsContextKey appContextKey = "permissions"
+	// ClientSubjectContextKey is the key for the subject of the verified TLS client certificate.
+	ClientSubjectContextKey appContextKey = "clientSubject"
+	// TrustedProxyContextKey is the key marking the connections of a listener behind a trusted proxy.
+	TrustedProxyContextKey appContextKey = "trustedProxy"
 )
+
+// Username returns the authenticated username stored in the context.
+func Username(ctx context.Context) (string, bool) {
+	username, ok := ctx.Value(UsernameContextKey).(string)
+
+	return username, ok
+}
+
+// Subject returns the ID of the authenticated user stored in the context.
+// It is missing for the anonymous tokens.
+func Subject(ctx context.Context) (string, bool) {
+	subject, ok := ctx.Value(SubjectContextKey).(string)
+
+	return subject, ok && subject != ""
+}
+
+// Permissions returns the permissions granted to the authenticated client stored in the context.
+func Permissions(ctx context.Context) []string {
+	permissions, _ := ctx.Value(PermissionsContextKey).([]string)
+
+	return permissions
+}
+
+// RequestID returns the request ID stored in the context.
+func RequestID(ctx context.Context) (string, bool) {
+	requestID, ok := ctx.Value(RequestIDContextKey).(string)
+
+	return requestID, ok
+}
+
+// ClientSubject returns the subject of the verified TLS client certificate stored in the context.
+func ClientSubject(ctx context.Context) (string, bool) {
+	subject, ok := ctx.Value(ClientSubjectContextKey).(string)
+
+	return subject, ok
+}
+
+// IsTrustedProxy reports whether the request came through a listener behind a trusted proxy,
+// whose X-Forwarded-For header holds the client address.
+func IsTrustedProxy(ctx context.Context) bool {
+	trusted, _ := ctx.Value(TrustedProxyContextKey).(bool)
+
+	return trusted
+}
@@ -0,0 +1,128 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+type (
+	// Hook is a function executed during the application start or stop phase.
+	// The provided context carries the deadline of the phase.
+	Hook func(ctx context.Context) error
+
+	// Worker is a long-running background function.
+	// It must return as soon as the provided context is canceled.
+	Worker func(ctx context.Context)
+
+	// Lifecycle keeps ordered lists of start and stop hooks.
+	// Start hooks are executed in registration order, stop hooks in reverse registration order,
+	// so resources are released in the opposite order of their acquisition.
+	Lifecycle struct {
+		mu         sync.Mutex
+		startHooks []namedHook
+		stopHooks  []namedHook
+	}
+
+	namedHook struct {
+		name string
+		hook Hook
+	}
+)
+
+// New creates a new empty Lifecycle.
+func New() *Lifecycle {
+	return &Lifecycle{}
+}
+
+// OnStart registers a hook executed when the application starts.
+func (l *Lifecycle) OnStart(name string, hook Hook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.startHooks = append(l.startHooks, namedHook{name: name, hook: hook})
+}
+
+// OnStop registers a hook executed when the application stops.
+func (l *Lifecycle) OnStop(name string, hook Hook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.stopHooks = append(l.stopHooks, namedHook{name: name, hook: hook})
+}
+
+// Go registers a background worker.
+// The worker is started with the start hooks and its context is canceled with the stop hooks.
+// The stop hook waits for the worker to return or for the stop deadline to expire.
+func (l *Lifecycle) Go(name string, worker Worker) {
+	var (
+		cancel context.CancelFunc
+		done   = make(chan struct{})
+	)
+
+	l.OnStart(name, func(context.Context) error {
+		var workerCtx context.Context
+
+		workerCtx, cancel = context.WithCancel(context.Background())
+
+		go func() {
+			defer close(done)
+			worker(workerCtx)
+		}()
+
+		return nil
+	})
+
+	l.OnStop(name, func(ctx context.Context) error {
+		if cancel == nil {
+			return nil
+		}
+
+		cancel()
+
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for worker to stop: %w", ctx.Err())
+		}
+	})
+}
+
+// Start executes the start hooks in registration order.
+// It stops on the first failed hook and returns its error.
+func (l *Lifecycle) Start(ctx context.Context) error {
+	for _, h := range l.hooks(&l.startHooks) {
+		if err := h.hook(ctx); err != nil {
+			return fmt.Errorf("start hook %q: %w", h.name, err)
+		}
+	}
+
+	return nil
+}
+
+// Stop executes the stop hooks in reverse registration order.
+// All hooks are executed even if some of them fail; the returned error joins all failures.
+func (l *Lifecycle) Stop(ctx context.Context) error {
+	hooks := l.hooks(&l.stopHooks)
+	errs := make([]error, 0, len(hooks))
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := hooks[i].hook(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("stop hook %q: %w", hooks[i].name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (l *Lifecycle) hooks(list *[]namedHook) []namedHook {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	hooks := make([]namedHook, len(*list))
+	copy(hooks, *list)
+
+	return hooks
+}
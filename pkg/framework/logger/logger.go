@@ -0,0 +1,118 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"solid-software.test-task/pkg/framework/config"
+	"solid-software.test-task/pkg/framework/ctxutils"
+)
+
+const (
+	formatJSON = "json"
+	formatText = "text"
+
+	// RequestIDKey is the attribute key of the request ID.
+	RequestIDKey = "request_id"
+)
+
+var (
+	_level  = new(slog.LevelVar)        //nolint:gochecknoglobals
+	_logger atomic.Pointer[slog.Logger] //nolint:gochecknoglobals
+)
+
+func init() { //nolint:gochecknoinits
+	_logger.Store(slog.New(newHandler(os.Stdout, formatText, nil)))
+}
+
+// Init configures the framework logger from the "log" config section
+// and makes it the default slog logger. The level follows the config reloads.
+func Init(cfg config.Config) error {
+	if err := SetLevel(cfg.GetString("log.level")); err != nil {
+		return err
+	}
+
+	cfg.OnChange("log.level", func() {
+		if err := SetLevel(cfg.GetString("log.level")); err != nil {
+			Default().Error("reload log level", slog.Any("error", err))
+
+			return
+		}
+
+		Default().Info("log level changed", slog.String("level", Level()))
+	})
+
+	format := strings.ToLower(cfg.GetString("log.format"))
+	if format == "" {
+		format = formatJSON
+	}
+
+	if format != formatJSON && format != formatText {
+		return fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+
+	logger := slog.New(newHandler(os.Stdout, format, cfg.GetStringSlice("log.redactKeys")))
+	_logger.Store(logger)
+	slog.SetDefault(logger)
+
+	return nil
+}
+
+// Default returns the framework logger.
+func Default() *slog.Logger {
+	return _logger.Load()
+}
+
+// FromContext returns the framework logger enriched with the request scoped attributes
+// stored in the context, e.g. the request ID.
+func FromContext(ctx context.Context) *slog.Logger {
+	logger := Default()
+
+	if requestID, ok := ctxutils.RequestID(ctx); ok {
+		return logger.With(RequestIDKey, requestID)
+	}
+
+	return logger
+}
+
+// SetLevel changes the level of the framework logger at runtime.
+// An empty level resets it to info.
+func SetLevel(level string) error {
+	if level == "" {
+		_level.Set(slog.LevelInfo)
+
+		return nil
+	}
+
+	var parsed slog.Level
+	if err := parsed.UnmarshalText([]byte(level)); err != nil {
+		return fmt.Errorf("%w: %q", ErrUnknownLevel, level)
+	}
+
+	_level.Set(parsed)
+
+	return nil
+}
+
+// Level returns the current level of the framework logger.
+func Level() string {
+	return _level.Level().String()
+}
+
+func newHandler(writer io.Writer, format string, redactKeys []string) slog.Handler {
+	options := &slog.HandlerOptions{
+		Level:       _level,
+		ReplaceAttr: newRedactor(redactKeys).replaceAttr,
+	}
+
+	if format == formatJSON {
+		return slog.NewJSONHandler(writer, options)
+	}
+
+	return slog.NewTextHandler(writer, options)
+}
@@ -0,0 +1,85 @@
+package logger
+
+import (
+	"errors"
+	"log/slog"
+	"net/url"
+	"strings"
+	"sync/atomic"
+)
+
+type (
+	// redactor hides the values of the attributes and query parameters considered sensitive.
+	redactor struct {
+		keys map[string]struct{}
+	}
+)
+
+const (
+	// RedactedValue replaces the sensitive values.
+	RedactedValue = "[REDACTED]"
+)
+
+var (
+	// ErrUnknownLevel is returned when the log level is not supported.
+	ErrUnknownLevel = errors.New("unknown log level")
+	// ErrUnknownFormat is returned when the log format is not supported.
+	ErrUnknownFormat = errors.New("unknown log format")
+
+	_defaultRedactKeys = []string{ //nolint:gochecknoglobals
+		"password", "token", "authorization", "secret", "phone", "address", "surname",
+	}
+	_redactor atomic.Pointer[redactor] //nolint:gochecknoglobals
+)
+
+func newRedactor(keys []string) *redactor {
+	if len(keys) == 0 {
+		keys = _defaultRedactKeys
+	}
+
+	r := &redactor{keys: make(map[string]struct{}, len(keys))}
+	for _, key := range keys {
+		r.keys[strings.ToLower(key)] = struct{}{}
+	}
+
+	_redactor.Store(r)
+
+	return r
+}
+
+func (r *redactor) isSensitive(key string) bool {
+	_, ok := r.keys[strings.ToLower(key)]
+
+	return ok
+}
+
+func (r *redactor) replaceAttr(_ []string, attr slog.Attr) slog.Attr {
+	if r.isSensitive(attr.Key) {
+		return slog.String(attr.Key, RedactedValue)
+	}
+
+	return attr
+}
+
+// RedactURI hides the values of the sensitive query parameters of the request URI.
+func RedactURI(requestURI string) string {
+	path, query, found := strings.Cut(requestURI, "?")
+	if !found {
+		return requestURI
+	}
+
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return path
+	}
+
+	r := _redactor.Load()
+
+	for key := range values {
+		if r != nil && r.isSensitive(key) {
+			values[key] = []string{RedactedValue}
+		}
+	}
+
+	return path + "?" + values.Encode()
+}
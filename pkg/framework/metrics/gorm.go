@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type (
+	// GormPlugin is a gorm plugin that records the count and the duration of the queries
+	// per table and operation.
+	GormPlugin struct{}
+)
+
+const (
+	gormPluginName     = "metrics"
+	gormStartTimeKey   = "metrics:start_time"
+	gormCallbackPrefix = "metrics:"
+)
+
+// NewGormPlugin creates a new gorm metrics plugin.
+func NewGormPlugin() *GormPlugin {
+	return &GormPlugin{}
+}
+
+// Name returns the name of the plugin.
+func (*GormPlugin) Name() string {
+	return gormPluginName
+}
+
+// Initialize registers the plugin callbacks around every gorm operation.
+func (*GormPlugin) Initialize(db *gorm.DB) error {
+	callback := db.Callback()
+
+	err := errors.Join(
+		callback.Create().Before("gorm:create").Register(beforeName("create"), startGormTimer),
+		callback.Create().After("gorm:create").Register(afterName("create"), recordGormQuery("create")),
+		callback.Query().Before("gorm:query").Register(beforeName("query"), startGormTimer),
+		callback.Query().After("gorm:query").Register(afterName("query"), recordGormQuery("query")),
+		callback.Update().Before("gorm:update").Register(beforeName("update"), startGormTimer),
+		callback.Update().After("gorm:update").Register(afterName("update"), recordGormQuery("update")),
+		callback.Delete().Before("gorm:delete").Register(beforeName("delete"), startGormTimer),
+		callback.Delete().After("gorm:delete").Register(afterName("delete"), recordGormQuery("delete")),
+		callback.Row().Before("gorm:row").Register(beforeName("row"), startGormTimer),
+		callback.Row().After("gorm:row").Register(afterName("row"), recordGormQuery("row")),
+		callback.Raw().Before("gorm:raw").Register(beforeName("raw"), startGormTimer),
+		callback.Raw().After("gorm:raw").Register(afterName("raw"), recordGormQuery("raw")),
+	)
+	if err != nil {
+		return fmt.Errorf("register metrics callbacks: %w", err)
+	}
+
+	return nil
+}
+
+func beforeName(operation string) string {
+	return gormCallbackPrefix + "before_" + operation
+}
+
+func afterName(operation string) string {
+	return gormCallbackPrefix + "after_" + operation
+}
+
+func startGormTimer(db *gorm.DB) {
+	db.InstanceSet(gormStartTimeKey, time.Now())
+}
+
+func recordGormQuery(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(gormStartTimeKey)
+		if !ok {
+			return
+		}
+
+		startTime, ok := value.(time.Time)
+		if !ok {
+			return
+		}
+
+		table := db.Statement.Table
+		if table == "" && db.Statement.Schema != nil {
+			table = db.Statement.Schema.Table
+		}
+
+		failed := db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound)
+
+		DBQueryFinished(table, operation, failed, time.Since(startTime))
+	}
+}
@@ -0,0 +1,118 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	namespace = "sstt"
+)
+
+var (
+	_registry = newRegistry() //nolint:gochecknoglobals
+
+	_httpRequestsTotal = promauto(prometheus.NewCounterVec( //nolint:gochecknoglobals
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Number of handled HTTP requests.",
+		},
+		[]string{"route", "method", "status"},
+	))
+	_httpRequestDuration = promauto(prometheus.NewHistogramVec( //nolint:gochecknoglobals
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of handled HTTP requests.",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"route", "method", "status"},
+	))
+	_httpRequestsInFlight = promauto(prometheus.NewGauge( //nolint:gochecknoglobals
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_in_flight",
+			Help:      "Number of HTTP requests being handled.",
+		},
+	))
+	_dbQueriesTotal = promauto(prometheus.NewCounterVec( //nolint:gochecknoglobals
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "db",
+			Name:      "queries_total",
+			Help:      "Number of executed database queries.",
+		},
+		[]string{"table", "operation", "status"},
+	))
+	_dbQueryDuration = promauto(prometheus.NewHistogramVec( //nolint:gochecknoglobals
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "db",
+			Name:      "query_duration_seconds",
+			Help:      "Latency of executed database queries.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		},
+		[]string{"table", "operation"},
+	))
+)
+
+func newRegistry() *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	return registry
+}
+
+func promauto[T prometheus.Collector](collector T) T {
+	_registry.MustRegister(collector)
+
+	return collector
+}
+
+// Handler returns the HTTP handler exposing the metrics in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(_registry, promhttp.HandlerOpts{Registry: _registry})
+}
+
+// RegisterDBStats registers the connection pool statistics of the database.
+func RegisterDBStats(db *sql.DB, dbName string) error {
+	return _registry.Register(collectors.NewDBStatsCollector(db, dbName)) //nolint:wrapcheck
+}
+
+// HTTPRequestStarted records a request entering the handler chain.
+func HTTPRequestStarted() {
+	_httpRequestsInFlight.Inc()
+}
+
+// HTTPRequestFinished records a handled request.
+func HTTPRequestFinished(route, method string, status int, duration time.Duration) {
+	_httpRequestsInFlight.Dec()
+
+	statusLabel := strconv.Itoa(status)
+	_httpRequestsTotal.WithLabelValues(route, method, statusLabel).Inc()
+	_httpRequestDuration.WithLabelValues(route, method, statusLabel).Observe(duration.Seconds())
+}
+
+// DBQueryFinished records an executed database query.
+func DBQueryFinished(table, operation string, failed bool, duration time.Duration) {
+	status := "ok"
+	if failed {
+		status = "error"
+	}
+
+	_dbQueriesTotal.WithLabelValues(table, operation, status).Inc()
+	_dbQueryDuration.WithLabelValues(table, operation).Observe(duration.Seconds())
+}
@@ -0,0 +1,243 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+
+	"solid-software.test-task/pkg/framework/config"
+)
+
+const (
+	// AlgorithmArgon2id hashes the passwords with argon2id, encoded in the PHC string format.
+	AlgorithmArgon2id = "argon2id"
+	// AlgorithmBcrypt hashes the passwords with bcrypt.
+	AlgorithmBcrypt = "bcrypt"
+
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+	argon2Format     = "$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s"
+	bcryptPrefix     = "$2"
+	kibibyte         = 1024
+	// bcryptMaxLength is the length of the passwords bcrypt accepts, in bytes.
+	bcryptMaxLength = 72
+)
+
+type (
+	// Hasher hashes and verifies the passwords with the "auth.password" config section.
+	Hasher struct {
+		policy *config.Watched[config.Password]
+	}
+
+	argon2Hash struct {
+		memory      uint32
+		iterations  uint32
+		parallelism uint8
+		salt        []byte
+		key         []byte
+	}
+)
+
+var (
+	// ErrMismatch is returned when the password doesn't match the hash.
+	ErrMismatch = errors.New("password does not match")
+	// ErrWeak is returned when the password doesn't satisfy the password policy.
+	ErrWeak = errors.New("password does not satisfy the policy")
+	// ErrUnknownHash is returned when the hash is not produced by a supported algorithm.
+	ErrUnknownHash = errors.New("unknown password hash format")
+	// ErrTooLong is returned when the password is longer than the hash algorithm accepts.
+	ErrTooLong = errors.New("password is too long for the hash algorithm")
+)
+
+// NewHasher returns the hasher following the password policy of the config section.
+func NewHasher(policy *config.Watched[config.Password]) *Hasher {
+	return &Hasher{policy: policy}
+}
+
+// Check checks the password against the policy: its length, the number of the character classes
+// (lower case and upper case letters, digits and other characters) and that it doesn't contain the username.
+// The length is counted in characters, and with bcrypt also in bytes, as bcrypt accepts up to 72 bytes.
+// It returns ErrWeak listing all the failures.
+func (h *Hasher) Check(username, password string) error {
+	policy := h.policy.Load()
+
+	var reasons []string
+
+	switch length := utf8.RuneCountInString(password); {
+	case length < policy.MinLength:
+		reasons = append(reasons, fmt.Sprintf("must be at least %d characters long", policy.MinLength))
+	case length > policy.MaxLength:
+		reasons = append(reasons, fmt.Sprintf("must be at most %d characters long", policy.MaxLength))
+	case policy.Algorithm == AlgorithmBcrypt && len(password) > bcryptMaxLength:
+		reasons = append(reasons, fmt.Sprintf("must be at most %d bytes long", bcryptMaxLength))
+	}
+
+	if classes := characterClasses(password); classes < policy.MinClasses {
+		reasons = append(reasons, fmt.Sprintf(
+			"must contain at least %d of lower case letters, upper case letters, digits and other characters",
+			policy.MinClasses,
+		))
+	}
+
+	if username != "" && strings.Contains(strings.ToLower(password), strings.ToLower(username)) {
+		reasons = append(reasons, "must not contain the username")
+	}
+
+	if len(reasons) > 0 {
+		return fmt.Errorf("%w: %s", ErrWeak, strings.Join(reasons, "; "))
+	}
+
+	return nil
+}
+
+// Hash hashes the password with the algorithm of the policy.
+// It returns ErrTooLong if the password is longer than the algorithm accepts.
+func (h *Hasher) Hash(password string) (string, error) {
+	policy := h.policy.Load()
+
+	if policy.Algorithm == AlgorithmBcrypt {
+		if len(password) > bcryptMaxLength {
+			return "", fmt.Errorf("hash password: %w", ErrTooLong)
+		}
+
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), policy.Bcrypt.Cost)
+		if err != nil {
+			return "", fmt.Errorf("hash password: %w", err)
+		}
+
+		return string(hash), nil
+	}
+
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("generate salt: %w", err)
+	}
+
+	hash := newArgon2Hash(policy.Argon2id, salt, password)
+
+	return hash.String(), nil
+}
+
+// Verify verifies the password against the hash. It returns ErrMismatch if the password doesn't match.
+// The rehash result reports whether the hash was produced with other algorithm or parameters than
+// the current policy ones, so the matching password should be hashed again.
+func (h *Hasher) Verify(hash, password string) (rehash bool, err error) {
+	policy := h.policy.Load()
+
+	if strings.HasPrefix(hash, bcryptPrefix) {
+		// bcrypt compares the first 72 bytes only, while the longer passwords are never hashed.
+		if len(password) > bcryptMaxLength {
+			return false, ErrMismatch
+		}
+
+		err = bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+
+		switch {
+		case errors.Is(err, bcrypt.ErrMismatchedHashAndPassword):
+			return false, ErrMismatch
+		case err != nil:
+			return false, fmt.Errorf("%w: %w", ErrUnknownHash, err)
+		}
+
+		cost, _ := bcrypt.Cost([]byte(hash))
+
+		return policy.Algorithm != AlgorithmBcrypt || cost != policy.Bcrypt.Cost, nil
+	}
+
+	stored, err := parseArgon2Hash(hash)
+	if err != nil {
+		return false, err
+	}
+
+	computed := argon2.IDKey(
+		[]byte(password), stored.salt, stored.iterations, stored.memory, stored.parallelism, uint32(len(stored.key)),
+	)
+	if subtle.ConstantTimeCompare(computed, stored.key) != 1 {
+		return false, ErrMismatch
+	}
+
+	current := newArgon2Params(policy.Argon2id)
+
+	return policy.Algorithm != AlgorithmArgon2id || stored.memory != current.memory ||
+		stored.iterations != current.iterations || stored.parallelism != current.parallelism, nil
+}
+
+func newArgon2Params(section config.Argon2id) argon2Hash {
+	return argon2Hash{
+		memory:      uint32(section.Memory / kibibyte),
+		iterations:  section.Iterations,
+		parallelism: section.Parallelism,
+	}
+}
+
+func newArgon2Hash(section config.Argon2id, salt []byte, password string) argon2Hash {
+	hash := newArgon2Params(section)
+	hash.salt = salt
+	hash.key = argon2.IDKey([]byte(password), salt, hash.iterations, hash.memory, hash.parallelism, argon2KeyLength)
+
+	return hash
+}
+
+// String encodes the hash in the PHC string format, e.g. "$argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>".
+func (a argon2Hash) String() string {
+	return fmt.Sprintf(argon2Format, argon2.Version, a.memory, a.iterations, a.parallelism,
+		base64.RawStdEncoding.EncodeToString(a.salt), base64.RawStdEncoding.EncodeToString(a.key))
+}
+
+func parseArgon2Hash(hash string) (argon2Hash, error) {
+	var (
+		parsed  argon2Hash
+		version int
+	)
+
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != AlgorithmArgon2id { //nolint:gomnd
+		return parsed, ErrUnknownHash
+	}
+
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return parsed, fmt.Errorf("%w: unsupported argon2 version %q", ErrUnknownHash, parts[2])
+	}
+
+	_, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &parsed.memory, &parsed.iterations, &parsed.parallelism)
+	if err != nil {
+		return parsed, fmt.Errorf("%w: parse argon2 parameters: %w", ErrUnknownHash, err)
+	}
+
+	if parsed.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return parsed, fmt.Errorf("%w: decode argon2 salt: %w", ErrUnknownHash, err)
+	}
+
+	if parsed.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil {
+		return parsed, fmt.Errorf("%w: decode argon2 key: %w", ErrUnknownHash, err)
+	}
+
+	return parsed, nil
+}
+
+func characterClasses(password string) int {
+	var lower, upper, digit, other int
+
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			other = 1
+		}
+	}
+
+	return lower + upper + digit + other
+}
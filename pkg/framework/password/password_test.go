@@ -0,0 +1,200 @@
+package password_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"solid-software.test-task/pkg/framework/config"
+	"solid-software.test-task/pkg/framework/password"
+)
+
+const (
+	storedPassword = "correct horse battery"
+)
+
+var (
+	_argon2idPolicy = config.Password{ //nolint:gochecknoglobals
+		Algorithm: password.AlgorithmArgon2id,
+		MinLength: 12, MaxLength: 64, MinClasses: 2,
+		Argon2id: config.Argon2id{Memory: 8192, Iterations: 1, Parallelism: 1},
+		Bcrypt:   config.Bcrypt{Cost: 4},
+	}
+	_bcryptPolicy = withAlgorithm(_argon2idPolicy, password.AlgorithmBcrypt) //nolint:gochecknoglobals
+)
+
+func TestHasherVerify(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		// hashedWith is the policy the stored password is hashed with.
+		hashedWith config.Password
+		// verifiedWith is the policy the password is verified with.
+		verifiedWith config.Password
+		password     string
+		wantErr      error
+		wantRehash   bool
+	}{
+		{
+			name:       "argon2id matching password",
+			hashedWith: _argon2idPolicy, verifiedWith: _argon2idPolicy,
+			password: storedPassword,
+		},
+		{
+			name:       "argon2id mismatching password",
+			hashedWith: _argon2idPolicy, verifiedWith: _argon2idPolicy,
+			password: storedPassword + "!", wantErr: password.ErrMismatch,
+		},
+		{
+			name:       "argon2id memory changed",
+			hashedWith: _argon2idPolicy, verifiedWith: withArgon2id(_argon2idPolicy, config.Argon2id{Memory: 16384}),
+			password: storedPassword, wantRehash: true,
+		},
+		{
+			name:       "argon2id iterations changed",
+			hashedWith: _argon2idPolicy, verifiedWith: withArgon2id(_argon2idPolicy, config.Argon2id{Iterations: 2}),
+			password: storedPassword, wantRehash: true,
+		},
+		{
+			name:       "argon2id parallelism changed",
+			hashedWith: _argon2idPolicy, verifiedWith: withArgon2id(_argon2idPolicy, config.Argon2id{Parallelism: 2}),
+			password: storedPassword, wantRehash: true,
+		},
+		{
+			name:       "argon2id replaced by bcrypt",
+			hashedWith: _argon2idPolicy, verifiedWith: _bcryptPolicy,
+			password: storedPassword, wantRehash: true,
+		},
+		{
+			name:       "bcrypt matching password",
+			hashedWith: _bcryptPolicy, verifiedWith: _bcryptPolicy,
+			password: storedPassword,
+		},
+		{
+			name:       "bcrypt mismatching password",
+			hashedWith: _bcryptPolicy, verifiedWith: _bcryptPolicy,
+			password: storedPassword + "!", wantErr: password.ErrMismatch,
+		},
+		{
+			name:       "bcrypt cost changed",
+			hashedWith: _bcryptPolicy, verifiedWith: withBcryptCost(_bcryptPolicy, 5),
+			password: storedPassword, wantRehash: true,
+		},
+		{
+			name:       "bcrypt replaced by argon2id",
+			hashedWith: _bcryptPolicy, verifiedWith: _argon2idPolicy,
+			password: storedPassword, wantRehash: true,
+		},
+		{
+			name:       "bcrypt password longer than 72 bytes with the stored prefix",
+			hashedWith: _bcryptPolicy, verifiedWith: _bcryptPolicy,
+			password: storedPassword + strings.Repeat("é", 30), wantErr: password.ErrMismatch,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			hash, err := newHasher(test.hashedWith).Hash(storedPassword)
+			if err != nil {
+				t.Fatalf("Hash() error = %v", err)
+			}
+
+			rehash, err := newHasher(test.verifiedWith).Verify(hash, test.password)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("Verify() error = %v, want %v", err, test.wantErr)
+			}
+
+			if rehash != test.wantRehash {
+				t.Errorf("Verify() rehash = %v, want %v", rehash, test.wantRehash)
+			}
+		})
+	}
+}
+
+func TestHasherCheck(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		policy   config.Password
+		username string
+		password string
+		wantErr  error
+	}{
+		{name: "strong password", policy: _argon2idPolicy, username: "eugene", password: storedPassword},
+		{name: "too short", policy: _argon2idPolicy, password: "Short1", wantErr: password.ErrWeak},
+		{name: "too long", policy: _argon2idPolicy, password: strings.Repeat("Ab1", 22), wantErr: password.ErrWeak},
+		{name: "too few classes", policy: _argon2idPolicy, password: "correcthorsebattery", wantErr: password.ErrWeak},
+		{
+			name: "contains the username", policy: _argon2idPolicy,
+			username: "Horse", password: storedPassword, wantErr: password.ErrWeak,
+		},
+		{name: "64 multibyte characters with argon2id", policy: _argon2idPolicy, password: "Aa1" + strings.Repeat("é", 61)},
+		{
+			name: "64 multibyte characters over 72 bytes with bcrypt", policy: _bcryptPolicy,
+			password: "Aa1" + strings.Repeat("é", 61), wantErr: password.ErrWeak,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := newHasher(test.policy).Check(test.username, test.password)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("Check() error = %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestHasherHashTooLong(t *testing.T) {
+	t.Parallel()
+
+	_, err := newHasher(_bcryptPolicy).Hash(strings.Repeat("a", 73))
+	if !errors.Is(err, password.ErrTooLong) {
+		t.Errorf("Hash() error = %v, want %v", err, password.ErrTooLong)
+	}
+}
+
+func newHasher(policy config.Password) *password.Hasher {
+	return password.NewHasher(config.NewWatched(config.NewConfig(), "auth.password", func(config.Config) config.Password {
+		return policy
+	}))
+}
+
+func withAlgorithm(policy config.Password, algorithm string) config.Password {
+	policy.Algorithm = algorithm
+
+	return policy
+}
+
+// withArgon2id overrides the non-zero argon2id parameters of the policy.
+func withArgon2id(policy config.Password, params config.Argon2id) config.Password {
+	if params.Memory != 0 {
+		policy.Argon2id.Memory = params.Memory
+	}
+
+	if params.Iterations != 0 {
+		policy.Argon2id.Iterations = params.Iterations
+	}
+
+	if params.Parallelism != 0 {
+		policy.Argon2id.Parallelism = params.Parallelism
+	}
+
+	return policy
+}
+
+func withBcryptCost(policy config.Password, cost int) config.Password {
+	policy.Bcrypt.Cost = cost
+
+	return policy
+}
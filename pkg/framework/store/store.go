@@ -7,6 +7,8 @@ import (
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/schema"
+
+	"solid-software.test-task/pkg/framework/tracing"
 )
 
 type (
@@ -58,6 +60,9 @@ func New[TEntity, TDBModel any](
 
 // Save stores the entity into the database.
 func (s *BaseStore[TEntity, TDBModel]) Save(ctx context.Context, entity *TEntity) error {
+	ctx, span := tracing.StartSpan(ctx, "BaseStore.Save")
+	defer span.End()
+
 	dbModel, err := s.FromEntity(ctx, entity)
 	if err != nil {
 		return fmt.Errorf("converting entity to DB model: %w", err)
@@ -80,6 +85,9 @@ func (s *BaseStore[TEntity, TDBModel]) Save(ctx context.Context, entity *TEntity
 
 // GetByID retrieves an entity given its ID.
 func (s *BaseStore[TEntity, TDBModel]) GetByID(ctx context.Context, entityID uint) (*TEntity, error) {
+	ctx, span := tracing.StartSpan(ctx, "BaseStore.GetByID")
+	defer span.End()
+
 	var dbModel TDBModel
 
 	const idColumn = "ID"
@@ -104,6 +112,9 @@ func (s *BaseStore[TEntity, TDBModel]) GetByID(ctx context.Context, entityID uin
 
 // RecordExistsByID checks whether a record exists with the given ID.
 func (s *BaseStore[TEntity, TDBModel]) RecordExistsByID(ctx context.Context, entityID uint) (bool, error) {
+	ctx, span := tracing.StartSpan(ctx, "BaseStore.RecordExistsByID")
+	defer span.End()
+
 	var dbModel TDBModel
 
 	const idColumn = "ID"
@@ -130,6 +141,9 @@ func (s *BaseStore[TEntity, TDBModel]) GetWithFilter(
 	ctx context.Context,
 	filters ...Filter,
 ) ([]TEntity, error) {
+	ctx, span := tracing.StartSpan(ctx, "BaseStore.GetWithFilter")
+	defer span.End()
+
 	var (
 		dbModels []TDBModel
 		dbModel  TDBModel
@@ -170,6 +184,9 @@ func (s *BaseStore[TEntity, TDBModel]) GetWithFilter(
 
 // DeleteByID deletes an entity represented by the given ID from the database.
 func (s *BaseStore[TEntity, TDBModel]) DeleteByID(ctx context.Context, entityID uint) error {
+	ctx, span := tracing.StartSpan(ctx, "BaseStore.DeleteByID")
+	defer span.End()
+
 	var dbModel TDBModel
 
 	const idColumn = "ID"
@@ -0,0 +1,105 @@
+package tracing
+
+import (
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+type (
+	// GormPlugin is a gorm plugin that creates a child span for every query
+	// of the span carried by the statement context.
+	GormPlugin struct{}
+)
+
+const (
+	gormPluginName     = "tracing"
+	gormSpanKey        = "tracing:span"
+	gormCallbackPrefix = "tracing:"
+)
+
+// NewGormPlugin creates a new gorm tracing plugin.
+func NewGormPlugin() *GormPlugin {
+	return &GormPlugin{}
+}
+
+// Name returns the name of the plugin.
+func (*GormPlugin) Name() string {
+	return gormPluginName
+}
+
+// Initialize registers the plugin callbacks around every gorm operation.
+func (*GormPlugin) Initialize(db *gorm.DB) error {
+	callback := db.Callback()
+
+	err := errors.Join(
+		callback.Create().Before("gorm:create").Register(beforeName("create"), startGormSpan("create")),
+		callback.Create().After("gorm:create").Register(afterName("create"), endGormSpan),
+		callback.Query().Before("gorm:query").Register(beforeName("query"), startGormSpan("query")),
+		callback.Query().After("gorm:query").Register(afterName("query"), endGormSpan),
+		callback.Update().Before("gorm:update").Register(beforeName("update"), startGormSpan("update")),
+		callback.Update().After("gorm:update").Register(afterName("update"), endGormSpan),
+		callback.Delete().Before("gorm:delete").Register(beforeName("delete"), startGormSpan("delete")),
+		callback.Delete().After("gorm:delete").Register(afterName("delete"), endGormSpan),
+		callback.Row().Before("gorm:row").Register(beforeName("row"), startGormSpan("row")),
+		callback.Row().After("gorm:row").Register(afterName("row"), endGormSpan),
+		callback.Raw().Before("gorm:raw").Register(beforeName("raw"), startGormSpan("raw")),
+		callback.Raw().After("gorm:raw").Register(afterName("raw"), endGormSpan),
+	)
+	if err != nil {
+		return fmt.Errorf("register tracing callbacks: %w", err)
+	}
+
+	return nil
+}
+
+func beforeName(operation string) string {
+	return gormCallbackPrefix + "before_" + operation
+}
+
+func afterName(operation string) string {
+	return gormCallbackPrefix + "after_" + operation
+}
+
+func startGormSpan(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		table := db.Statement.Table
+		if table == "" && db.Statement.Schema != nil {
+			table = db.Statement.Schema.Table
+		}
+
+		_, span := StartSpan(
+			db.Statement.Context,
+			"gorm."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(semconv.DBSystemSqlite, semconv.DBOperation(operation), semconv.DBSQLTable(table)),
+		)
+
+		db.InstanceSet(gormSpanKey, span)
+	}
+}
+
+func endGormSpan(db *gorm.DB) {
+	value, ok := db.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+
+	span.SetAttributes(semconv.DBStatement(db.Statement.SQL.String()))
+
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+
+	span.End()
+}
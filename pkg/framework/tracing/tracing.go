@@ -0,0 +1,133 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"solid-software.test-task/pkg/framework/config"
+)
+
+type (
+	// ShutdownFunc flushes the pending spans and releases the exporter.
+	ShutdownFunc func(ctx context.Context) error
+)
+
+const (
+	instrumentationName = "solid-software.test-task"
+	defaultServiceName  = "api-server"
+
+	exporterOTLP   = "otlp"
+	exporterStdout = "stdout"
+	exporterFile   = "file"
+)
+
+var (
+	// ErrUnknownExporter is returned when the configured span exporter is not supported.
+	ErrUnknownExporter = errors.New("unknown trace exporter")
+)
+
+// Init configures the global tracer provider and the W3C trace context propagator
+// from the "tracing" config section.
+// If tracing is disabled, the no-op tracer provider stays in place and the returned function does nothing.
+func Init(ctx context.Context, cfg config.Config) (ShutdownFunc, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	if !cfg.GetBool("tracing.enabled") {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, closeOutput, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceName := cfg.GetString("tracing.serviceName")
+	if serviceName == "" {
+		serviceName = defaultServiceName
+	}
+
+	sampleRatio := 1.0
+	if cfg.GetString("tracing.sampleRatio") != "" {
+		sampleRatio = cfg.GetFloat64("tracing.sampleRatio")
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(serviceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closeOutput != nil {
+			err = errors.Join(err, closeOutput.Close())
+		}
+
+		if err != nil {
+			return fmt.Errorf("shutdown tracer provider: %w", err)
+		}
+
+		return nil
+	}, nil
+}
+
+// Tracer returns the tracer of the application.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// StartSpan starts a child span of the span carried by the context.
+func StartSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, opts...) //nolint:spancheck
+}
+
+func newExporter(ctx context.Context, cfg config.Config) (sdktrace.SpanExporter, io.Closer, error) {
+	switch exporter := cfg.GetString("tracing.exporter"); exporter {
+	case "", exporterOTLP:
+		options := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.GetString("tracing.otlp.endpoint"))}
+		if cfg.GetBool("tracing.otlp.insecure") {
+			options = append(options, otlptracehttp.WithInsecure())
+		}
+
+		otlpExporter, err := otlptracehttp.New(ctx, options...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("create otlp trace exporter: %w", err)
+		}
+
+		return otlpExporter, nil, nil
+	case exporterStdout:
+		stdoutExporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, nil, fmt.Errorf("create stdout trace exporter: %w", err)
+		}
+
+		return stdoutExporter, nil, nil
+	case exporterFile:
+		file, err := os.OpenFile(cfg.GetString("tracing.file"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600) //nolint:gomnd
+		if err != nil {
+			return nil, nil, fmt.Errorf("open trace file: %w", err)
+		}
+
+		fileExporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			return nil, nil, errors.Join(fmt.Errorf("create file trace exporter: %w", err), file.Close())
+		}
+
+		return fileExporter, file, nil
+	default:
+		return nil, nil, fmt.Errorf("%w: %q", ErrUnknownExporter, exporter)
+	}
+}
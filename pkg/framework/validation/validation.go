@@ -0,0 +1,346 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+const (
+	// Tag is the struct tag holding the validation rules, e.g. `validate:"required,max=64"`.
+	// The rules are separated by commas; the "pattern" rule must be the last one, as its regular expression may contain commas.
+	Tag = "validate"
+
+	// RuleRequired requires the field to be set.
+	RuleRequired = "required"
+	// RuleMin limits the minimal length of a string or the minimal value of a number, e.g. "min=2".
+	RuleMin = "min"
+	// RuleMax limits the maximal length of a string or the maximal value of a number, e.g. "max=64".
+	RuleMax = "max"
+	// RuleE164 requires a string to be a phone number in the E.164 format.
+	RuleE164 = "e164"
+	// RulePattern requires a string to match the regular expression, e.g. "pattern=^[a-z]+$".
+	RulePattern = "pattern"
+)
+
+type (
+	// InvalidParam describes a field which failed the validation.
+	InvalidParam struct {
+		// Name is the JSON name of the field.
+		Name string `json:"name"`
+		// Pointer is the JSON pointer (RFC 6901) of the field in the request body.
+		Pointer string `json:"pointer"`
+		// Reason describes the failure.
+		Reason string `json:"reason"`
+	}
+
+	// Error is returned when the value failed the validation. It lists all the failures.
+	Error struct {
+		InvalidParams []InvalidParam
+	}
+
+	// Rule describes a single validation rule of a field. It is exported for the API documentation.
+	Rule struct {
+		// Name is the rule name, e.g. "max".
+		Name string
+		// Length is the limit of the "min" and "max" rules: the length of a string or the value of a number.
+		Length int
+		// Pattern is the regular expression of the "pattern" and "e164" rules.
+		Pattern *regexp.Regexp
+	}
+
+	field struct {
+		index   int
+		name    string
+		rules   []Rule
+		nested  bool
+		pointer bool
+	}
+)
+
+var (
+	// ErrInvalidRule is returned when a validation tag can't be parsed.
+	ErrInvalidRule = errors.New("invalid validation rule")
+
+	// e164Pattern matches the phone numbers in the E.164 format, e.g. "+380991112233".
+	e164Pattern = regexp.MustCompile(`^\+[1-9]\d{1,14}$`)
+
+	// _fields caches the parsed fields of the struct types.
+	_fields sync.Map //nolint:gochecknoglobals
+)
+
+// Error returns the failures joined into a single message.
+func (e *Error) Error() string {
+	reasons := make([]string, 0, len(e.InvalidParams))
+	for _, param := range e.InvalidParams {
+		reasons = append(reasons, param.Name+": "+param.Reason)
+	}
+
+	return "validation failed: " + strings.Join(reasons, "; ")
+}
+
+// Validate checks the struct against the rules of its validate tags, descending into the nested structs.
+// It returns an *Error listing all the failures, or nil if the value is valid.
+// It panics if a tag can't be parsed, as that is a programming error.
+func Validate(v any) error {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil
+		}
+
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var params []InvalidParam
+
+	validateStruct(value, "", &params)
+
+	if len(params) > 0 {
+		return &Error{InvalidParams: params}
+	}
+
+	return nil
+}
+
+// RulesOf returns the validation rules of the struct fields keyed by their JSON names.
+func RulesOf(t reflect.Type) map[string][]Rule {
+	rules := map[string][]Rule{}
+
+	for _, f := range fieldsOf(t) {
+		if len(f.rules) > 0 {
+			rules[f.name] = f.rules
+		}
+	}
+
+	return rules
+}
+
+func validateStruct(value reflect.Value, pointer string, params *[]InvalidParam) {
+	for _, f := range fieldsOf(value.Type()) {
+		fieldValue := value.Field(f.index)
+		fieldPointer := pointer + "/" + escapePointer(f.name)
+
+		for _, rule := range f.rules {
+			if reason := rule.check(fieldValue); reason != "" {
+				*params = append(*params, InvalidParam{Name: f.name, Pointer: fieldPointer, Reason: reason})
+
+				break
+			}
+		}
+
+		if !f.nested {
+			continue
+		}
+
+		if f.pointer {
+			if fieldValue.IsNil() {
+				continue
+			}
+
+			fieldValue = fieldValue.Elem()
+		}
+
+		validateStruct(fieldValue, fieldPointer, params)
+	}
+}
+
+func (r Rule) check(value reflect.Value) string {
+	if r.Name == RuleRequired {
+		if value.IsZero() {
+			return "is required"
+		}
+
+		return ""
+	}
+
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return ""
+		}
+
+		value = value.Elem()
+	}
+
+	if number, ok := numberOf(value); ok {
+		return r.checkNumber(number)
+	}
+
+	// the remaining rules apply to the set strings only, so optional fields may be left empty.
+	if value.Kind() != reflect.String || value.Len() == 0 {
+		return ""
+	}
+
+	text := value.String()
+
+	switch r.Name {
+	case RuleMin:
+		if utf8.RuneCountInString(text) < r.Length {
+			return fmt.Sprintf("must be at least %d characters long", r.Length)
+		}
+	case RuleMax:
+		if utf8.RuneCountInString(text) > r.Length {
+			return fmt.Sprintf("must be at most %d characters long", r.Length)
+		}
+	case RuleE164:
+		if !r.Pattern.MatchString(text) {
+			return "must be a phone number in the E.164 format, e.g. +380991112233"
+		}
+	case RulePattern:
+		if !r.Pattern.MatchString(text) {
+			return fmt.Sprintf("must match the pattern %s", r.Pattern)
+		}
+	}
+
+	return ""
+}
+
+// checkNumber checks the value of a number against the "min" and "max" rules; the other rules don't apply to numbers.
+func (r Rule) checkNumber(number float64) string {
+	switch {
+	case r.Name == RuleMin && number < float64(r.Length):
+		return fmt.Sprintf("must be at least %d", r.Length)
+	case r.Name == RuleMax && number > float64(r.Length):
+		return fmt.Sprintf("must be at most %d", r.Length)
+	}
+
+	return ""
+}
+
+func numberOf(value reflect.Value) (float64, bool) {
+	switch value.Kind() { //nolint:exhaustive
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(value.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), true
+	}
+
+	return 0, false
+}
+
+func fieldsOf(t reflect.Type) []field {
+	if cached, ok := _fields.Load(t); ok {
+		return cached.([]field) //nolint:forcetypeassert
+	}
+
+	var fields []field
+
+	for i := 0; i < t.NumField(); i++ {
+		structField := t.Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+
+		name, skip := jsonName(structField)
+		if skip {
+			continue
+		}
+
+		rules, err := parseRules(structField.Tag.Get(Tag))
+		if err != nil {
+			panic(fmt.Errorf("field %s.%s: %w", t.Name(), structField.Name, err))
+		}
+
+		fieldType := structField.Type
+		isPointer := fieldType.Kind() == reflect.Pointer
+
+		if isPointer {
+			fieldType = fieldType.Elem()
+		}
+
+		fields = append(fields, field{
+			index:   i,
+			name:    name,
+			rules:   rules,
+			nested:  fieldType.Kind() == reflect.Struct && hasRules(fieldType),
+			pointer: isPointer,
+		})
+	}
+
+	_fields.Store(t, fields)
+
+	return fields
+}
+
+func hasRules(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if _, ok := t.Field(i).Tag.Lookup(Tag); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func parseRules(tag string) ([]Rule, error) {
+	var rules []Rule
+
+	for tag != "" {
+		var definition string
+
+		if strings.HasPrefix(tag, RulePattern+"=") {
+			definition, tag = tag, ""
+		} else {
+			definition, tag, _ = strings.Cut(tag, ",")
+		}
+
+		name, argument, _ := strings.Cut(strings.TrimSpace(definition), "=")
+		rule := Rule{Name: name}
+
+		switch name {
+		case RuleRequired:
+		case RuleMin, RuleMax:
+			length, err := strconv.Atoi(argument)
+			if err != nil || length < 0 {
+				return nil, fmt.Errorf("%w: %q", ErrInvalidRule, definition)
+			}
+
+			rule.Length = length
+		case RuleE164:
+			rule.Pattern = e164Pattern
+		case RulePattern:
+			pattern, err := regexp.Compile(argument)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %q: %w", ErrInvalidRule, definition, err)
+			}
+
+			rule.Pattern = pattern
+		default:
+			return nil, fmt.Errorf("%w: %q", ErrInvalidRule, definition)
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// jsonName returns the JSON member name of the field as defined by encoding/json.
+func jsonName(structField reflect.StructField) (string, bool) {
+	tag := structField.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name, false
+	}
+
+	return structField.Name, false
+}
+
+// escapePointer escapes the JSON pointer reference token as defined by RFC 6901.
+func escapePointer(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
@@ -0,0 +1,31 @@
+package webservice
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+
+	"solid-software.test-task/pkg/framework/webservice/admin"
+)
+
+// createAdminServer creates the internal listener serving the admin endpoints.
+// It returns nil if the admin listener is disabled.
+func (w *webService) createAdminServer() (*server, error) {
+	if !w.config.GetBool("admin.enabled") {
+		return nil, nil //nolint:nilnil
+	}
+
+	handler, err := admin.NewHandler(w.config)
+	if err != nil {
+		return nil, fmt.Errorf("create admin handler: %w", err)
+	}
+
+	addr := fmt.Sprintf("%s:%d", w.config.GetString("admin.host"), w.config.GetUint("admin.port"))
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("listen %q: %w", addr, err)
+	}
+
+	return &server{host: w.application.NewHost(&http.Server{Addr: addr, Handler: handler}), listener: listener}, nil
+}
@@ -0,0 +1,282 @@
+package admin
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"solid-software.test-task/pkg/framework/config"
+	"solid-software.test-task/pkg/framework/logger"
+	"solid-software.test-task/pkg/framework/webservice/maintenance"
+)
+
+const (
+	bearerPrefix = "Bearer "
+)
+
+type (
+	buildInfoResponse struct {
+		GoVersion string            `json:"goVersion"`
+		Path      string            `json:"path"`
+		Version   string            `json:"version"`
+		Settings  map[string]string `json:"settings"`
+	}
+
+	runtimeResponse struct {
+		Goroutines int       `json:"goroutines"`
+		GOMAXPROCS int       `json:"gomaxprocs"`
+		NumCPU     int       `json:"numCpu"`
+		HeapAlloc  uint64    `json:"heapAllocBytes"`
+		NumGC      uint32    `json:"numGc"`
+		StartedAt  time.Time `json:"startedAt"`
+		Uptime     string    `json:"uptime"`
+	}
+
+	logLevelRequest struct {
+		Level string `json:"level"`
+	}
+
+	maintenanceRequest struct {
+		Enabled    bool   `json:"enabled"`
+		Message    string `json:"message,omitempty"`
+		RetryAfter string `json:"retryAfter,omitempty"`
+	}
+
+	maintenanceResponse struct {
+		Enabled    bool      `json:"enabled"`
+		Message    string    `json:"message"`
+		RetryAfter string    `json:"retryAfter"`
+		Since      time.Time `json:"since"`
+	}
+
+	problem struct {
+		Title  string `json:"title"`
+		Status int    `json:"status"`
+		Detail string `json:"detail,omitempty"`
+	}
+)
+
+var (
+	// ErrTokenNotConfigured is returned when the admin listener is enabled without its credential.
+	ErrTokenNotConfigured = errors.New("admin token is not configured")
+
+	_startedAt = time.Now() //nolint:gochecknoglobals
+)
+
+// NewHandler returns the handler of the admin endpoints, protected by the "admin.token" bearer credential:
+//
+//	/debug/pprof/  the net/http/pprof profiles
+//	/config        the effective config with the secrets redacted
+//	/buildinfo     the module and VCS information of the binary
+//	/runtime       the goroutine count and the memory statistics
+//	/log/level     GET returns, PUT {"level": "debug"} changes the log level
+//	/cache/drop    POST drops the cached config values
+//	/config/reload POST reloads the config file
+//	/maintenance   GET returns, PUT {"enabled": true, "message": "...", "retryAfter": "10m"} switches the maintenance mode
+func NewHandler(cfg config.Config) (http.Handler, error) {
+	token := cfg.GetString("admin.token")
+	if token == "" {
+		return nil, ErrTokenNotConfigured
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.HandleFunc("/config", only(http.MethodGet, handleConfig))
+	mux.HandleFunc("/buildinfo", only(http.MethodGet, handleBuildInfo))
+	mux.HandleFunc("/runtime", only(http.MethodGet, handleRuntime))
+	mux.HandleFunc("/log/level", handleLogLevel)
+	mux.HandleFunc("/cache/drop", only(http.MethodPost, handleCacheDrop))
+	mux.HandleFunc("/config/reload", only(http.MethodPost, handleConfigReload))
+	mux.HandleFunc("/maintenance", handleMaintenance)
+
+	return Authenticate("admin", token, mux), nil
+}
+
+// Authenticate rejects the requests without the bearer token, challenging them in the realm.
+func Authenticate(realm, token string, next http.Handler) http.Handler {
+	expected := []byte(token)
+	challenge := fmt.Sprintf("Bearer realm=%q", realm)
+
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		provided, ok := strings.CutPrefix(request.Header.Get("Authorization"), bearerPrefix)
+		if !ok || subtle.ConstantTimeCompare([]byte(provided), expected) != 1 {
+			writer.Header().Set("WWW-Authenticate", challenge)
+			writeProblem(writer, http.StatusUnauthorized, "")
+
+			return
+		}
+
+		next.ServeHTTP(writer, request)
+	})
+}
+
+func only(method string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != method {
+			writer.Header().Set("Allow", method)
+			writeProblem(writer, http.StatusMethodNotAllowed, "")
+
+			return
+		}
+
+		handler(writer, request)
+	}
+}
+
+func handleConfig(writer http.ResponseWriter, _ *http.Request) {
+	writeJSON(writer, http.StatusOK, config.RedactedSettings())
+}
+
+func handleBuildInfo(writer http.ResponseWriter, _ *http.Request) {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		writeProblem(writer, http.StatusNotFound, "the binary is built without the module support")
+
+		return
+	}
+
+	response := buildInfoResponse{
+		GoVersion: info.GoVersion,
+		Path:      info.Main.Path,
+		Version:   info.Main.Version,
+		Settings:  map[string]string{},
+	}
+
+	for _, setting := range info.Settings {
+		response.Settings[setting.Key] = setting.Value
+	}
+
+	writeJSON(writer, http.StatusOK, response)
+}
+
+func handleRuntime(writer http.ResponseWriter, _ *http.Request) {
+	var memStats runtime.MemStats
+
+	runtime.ReadMemStats(&memStats)
+
+	writeJSON(writer, http.StatusOK, runtimeResponse{
+		Goroutines: runtime.NumGoroutine(),
+		GOMAXPROCS: runtime.GOMAXPROCS(0),
+		NumCPU:     runtime.NumCPU(),
+		HeapAlloc:  memStats.HeapAlloc,
+		NumGC:      memStats.NumGC,
+		StartedAt:  _startedAt,
+		Uptime:     time.Since(_startedAt).Round(time.Second).String(),
+	})
+}
+
+func handleLogLevel(writer http.ResponseWriter, request *http.Request) {
+	switch request.Method {
+	case http.MethodGet:
+		writeJSON(writer, http.StatusOK, logLevelRequest{Level: logger.Level()})
+	case http.MethodPut:
+		var levelRQ logLevelRequest
+
+		if err := json.NewDecoder(request.Body).Decode(&levelRQ); err != nil {
+			writeProblem(writer, http.StatusBadRequest, fmt.Sprintf("parse JSON: %s", err))
+
+			return
+		}
+
+		if err := logger.SetLevel(levelRQ.Level); err != nil {
+			writeProblem(writer, http.StatusBadRequest, err.Error())
+
+			return
+		}
+
+		logger.Default().Info("log level changed", slog.String("level", logger.Level()))
+		writeJSON(writer, http.StatusOK, logLevelRequest{Level: logger.Level()})
+	default:
+		writer.Header().Set("Allow", http.MethodGet+", "+http.MethodPut)
+		writeProblem(writer, http.StatusMethodNotAllowed, "")
+	}
+}
+
+func handleMaintenance(writer http.ResponseWriter, request *http.Request) {
+	switch request.Method {
+	case http.MethodGet:
+		writeJSON(writer, http.StatusOK, toMaintenanceResponse(maintenance.Current()))
+	case http.MethodPut:
+		var maintenanceRQ maintenanceRequest
+
+		if err := json.NewDecoder(request.Body).Decode(&maintenanceRQ); err != nil {
+			writeProblem(writer, http.StatusBadRequest, fmt.Sprintf("parse JSON: %s", err))
+
+			return
+		}
+
+		var retryAfter time.Duration
+
+		if maintenanceRQ.RetryAfter != "" {
+			var err error
+			if retryAfter, err = time.ParseDuration(maintenanceRQ.RetryAfter); err != nil {
+				writeProblem(writer, http.StatusBadRequest, fmt.Sprintf("parse retryAfter: %s", err))
+
+				return
+			}
+		}
+
+		status := maintenance.Set(maintenanceRQ.Enabled, maintenanceRQ.Message, retryAfter)
+		writeJSON(writer, http.StatusOK, toMaintenanceResponse(status))
+	default:
+		writer.Header().Set("Allow", http.MethodGet+", "+http.MethodPut)
+		writeProblem(writer, http.StatusMethodNotAllowed, "")
+	}
+}
+
+func toMaintenanceResponse(status maintenance.Status) maintenanceResponse {
+	return maintenanceResponse{
+		Enabled:    status.Enabled,
+		Message:    status.Message,
+		RetryAfter: status.RetryAfter.String(),
+		Since:      status.Since,
+	}
+}
+
+func handleConfigReload(writer http.ResponseWriter, _ *http.Request) {
+	if err := config.Reload(); err != nil {
+		writeProblem(writer, http.StatusInternalServerError, err.Error())
+
+		return
+	}
+
+	logger.Default().Info("config reloaded")
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+func handleCacheDrop(writer http.ResponseWriter, _ *http.Request) {
+	config.DropCache()
+	logger.Default().Info("config cache dropped")
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+func writeJSON(writer http.ResponseWriter, status int, value any) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+
+	if err := json.NewEncoder(writer).Encode(value); err != nil {
+		logger.Default().Error("write admin response", slog.Any("error", err))
+	}
+}
+
+func writeProblem(writer http.ResponseWriter, status int, detail string) {
+	writer.Header().Set("Content-Type", "application/problem+json")
+	writer.WriteHeader(status)
+
+	err := json.NewEncoder(writer).Encode(problem{Title: http.StatusText(status), Status: status, Detail: detail})
+	if err != nil {
+		logger.Default().Error("write admin response", slog.Any("error", err))
+	}
+}
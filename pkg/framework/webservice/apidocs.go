@@ -0,0 +1,128 @@
+package webservice
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/core/router"
+	swaggerFiles "github.com/swaggo/files/v2"
+
+	"solid-software.test-task/pkg/framework/webservice/openapi"
+	"solid-software.test-task/pkg/framework/webservice/route"
+	"solid-software.test-task/pkg/framework/webservice/versioning"
+)
+
+const (
+	apiDocumentPath = "/api/openapi.json"
+	apiDocsPath     = "/api/docs"
+	apiDocsIndex    = "index.html"
+
+	defaultAPITitle   = "Simple API server for user accounting"
+	defaultAPIVersion = "1.0.0"
+
+	// swaggerInitializer configures the bundled Swagger UI to load the generated document.
+	swaggerInitializer = `window.onload = function() {
+  window.ui = SwaggerUIBundle({
+    url: %q,
+    dom_id: '#swagger-ui',
+    deepLinking: true,
+    presets: [
+      SwaggerUIBundle.presets.apis,
+      SwaggerUIStandalonePreset
+    ],
+    plugins: [
+      SwaggerUIBundle.plugins.DownloadUrl
+    ],
+    layout: "StandaloneLayout"
+  });
+};
+`
+)
+
+// newAPIDocsBuilder returns the builder of the API document, or nil if the API documentation is disabled.
+func (w *webService) newAPIDocsBuilder() *openapi.Builder {
+	if !w.config.GetBool("openapi.enabled") {
+		return nil
+	}
+
+	title := w.config.GetString("openapi.title")
+	if title == "" {
+		title = defaultAPITitle
+	}
+
+	version := w.config.GetString("openapi.version")
+	if version == "" {
+		version = defaultAPIVersion
+	}
+
+	return openapi.NewBuilder(title, version)
+}
+
+// registerAPIDocsEndpoints serves the generated API document and the Swagger UI outside the API root.
+func (w *webService) registerAPIDocsEndpoints() {
+	if w.apiDocs == nil {
+		return
+	}
+
+	// the document is complete once the endpoints are registered, before the service starts listening.
+	w.application.Get(apiDocumentPath, func(irisCtx iris.Context) {
+		_ = irisCtx.JSON(w.apiDocs.Document())
+	})
+
+	// the UI is served from its index file, so the relative asset links resolve inside the docs path.
+	w.application.Get(apiDocsPath, func(irisCtx iris.Context) {
+		irisCtx.Redirect(apiDocsPath+"/"+apiDocsIndex, iris.StatusFound)
+	})
+	w.application.Get(apiDocsPath+"/{file:path}", serveSwaggerUI)
+}
+
+func serveSwaggerUI(irisCtx iris.Context) {
+	file := irisCtx.Params().Get("file")
+
+	if file == "swagger-initializer.js" {
+		irisCtx.ContentType("text/javascript")
+		_, _ = irisCtx.WriteString(fmt.Sprintf(swaggerInitializer, apiDocumentPath))
+
+		return
+	}
+
+	content, err := fs.ReadFile(swaggerFiles.FS, file)
+	if err != nil {
+		irisCtx.StatusCode(iris.StatusNotFound)
+
+		return
+	}
+
+	irisCtx.ServeContent(bytes.NewReader(content), file, time.Time{})
+}
+
+// documentRoutes adds the newly registered endpoints of the route to the API document.
+// Endpoints without a description are documented from their path only.
+// The endpoints of a deprecated API version are marked deprecated.
+func (w *webService) documentRoutes(r route.Route, version versioning.Version, registered []*router.Route) {
+	if w.apiDocs == nil {
+		return
+	}
+
+	operations := route.OperationsOf(r)
+
+	for _, registeredRoute := range registered {
+		var description *route.Operation
+
+		// the template keeps the path parameters as declared, e.g. "{id:uint}".
+		path := registeredRoute.Tmpl().Src
+
+		for i := range operations {
+			if operations[i].Method == registeredRoute.Method && version.Path+operations[i].Path == path {
+				description = &operations[i]
+
+				break
+			}
+		}
+
+		w.apiDocs.AddRoute(registeredRoute.Method, path, r.IsProtected(), version.Deprecated, description)
+	}
+}
@@ -0,0 +1,192 @@
+package codec
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type (
+	// Codec encodes response bodies to and decodes request bodies from a media type.
+	Codec interface {
+		// MediaType returns the media type of the codec, e.g. "application/json".
+		MediaType() string
+		// Aliases returns the alternative media types accepted for the codec, e.g. "text/xml".
+		Aliases() []string
+		// CanEncode reports whether the value can be represented in the media type.
+		CanEncode(v any) bool
+		// Encode writes the value to the writer.
+		Encode(w io.Writer, v any) error
+		// CanDecode reports whether request bodies of the media type can be decoded.
+		CanDecode() bool
+		// Decode reads the value from the reader.
+		Decode(r io.Reader, v any) error
+	}
+
+	// Registry holds the codecs available for the content negotiation.
+	// The first registered codec is the default one.
+	Registry struct {
+		mu     sync.RWMutex
+		codecs []Codec
+	}
+
+	// acceptedRange is a media range of the Accept header with its quality.
+	acceptedRange struct {
+		mediaType string
+		quality   float64
+	}
+)
+
+var (
+	// ErrNotAcceptable is returned when no codec can encode the response in an accepted media type.
+	ErrNotAcceptable = errors.New("none of the accepted media types can represent the response")
+	// ErrUnsupportedMediaType is returned when no codec can decode the request body media type.
+	ErrUnsupportedMediaType = errors.New("unsupported request body media type")
+
+	// Default is the registry of the JSON, MessagePack, XML, YAML and CSV codecs, JSON being the default one.
+	Default = NewRegistry(JSON(), MessagePack(), XML(), YAML(), CSV())
+)
+
+// NewRegistry returns a new registry of the codecs. The first codec is the default one.
+func NewRegistry(codecs ...Codec) *Registry {
+	return &Registry{codecs: codecs}
+}
+
+// Register adds the codec to the registry, replacing the codec of the same media type if any.
+func (r *Registry) Register(codec Codec) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, registered := range r.codecs {
+		if registered.MediaType() == codec.MediaType() {
+			r.codecs[i] = codec
+
+			return
+		}
+	}
+
+	r.codecs = append(r.codecs, codec)
+}
+
+// Negotiate returns the codec encoding the value in the most preferred media type of the Accept header.
+// An empty header accepts any media type.
+func (r *Registry) Negotiate(accept string, v any) (Codec, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if strings.TrimSpace(accept) == "" {
+		accept = "*/*"
+	}
+
+	for _, accepted := range parseAccept(accept) {
+		for _, codec := range r.codecs {
+			if matchesRange(codec, accepted.mediaType) && codec.CanEncode(v) {
+				return codec, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrNotAcceptable, accept)
+}
+
+// ForContentType returns the codec decoding the request body of the Content-Type header.
+// An empty header is decoded by the default codec.
+func (r *Registry) ForContentType(contentType string) (Codec, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if strings.TrimSpace(contentType) == "" {
+		if len(r.codecs) == 0 {
+			return nil, ErrUnsupportedMediaType
+		}
+
+		return r.codecs[0], nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedMediaType, contentType)
+	}
+
+	for _, codec := range r.codecs {
+		if matchesType(codec, mediaType) && codec.CanDecode() {
+			return codec, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedMediaType, mediaType)
+}
+
+// MediaTypes returns the media types of the registered codecs.
+func (r *Registry) MediaTypes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	mediaTypes := make([]string, 0, len(r.codecs))
+	for _, codec := range r.codecs {
+		mediaTypes = append(mediaTypes, codec.MediaType())
+	}
+
+	return mediaTypes
+}
+
+// parseAccept returns the media ranges of the Accept header ordered by quality.
+// Ranges with zero quality are not acceptable and are skipped.
+func parseAccept(accept string) []acceptedRange {
+	var ranges []acceptedRange
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+
+		if quality > 0 {
+			ranges = append(ranges, acceptedRange{mediaType: mediaType, quality: quality})
+		}
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].quality > ranges[j].quality
+	})
+
+	return ranges
+}
+
+func matchesRange(codec Codec, mediaRange string) bool {
+	if mediaRange == "*/*" {
+		return true
+	}
+
+	if prefix, ok := strings.CutSuffix(mediaRange, "/*"); ok {
+		return strings.HasPrefix(codec.MediaType(), prefix+"/")
+	}
+
+	return matchesType(codec, mediaRange)
+}
+
+func matchesType(codec Codec, mediaType string) bool {
+	if codec.MediaType() == mediaType {
+		return true
+	}
+
+	for _, alias := range codec.Aliases() {
+		if alias == mediaType {
+			return true
+		}
+	}
+
+	return false
+}
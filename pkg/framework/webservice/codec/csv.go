@@ -0,0 +1,201 @@
+package codec
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const (
+	// formulaPrefixes start the cells the spreadsheets evaluate as formulas.
+	formulaPrefixes = "=+-@\t\r"
+)
+
+type (
+	csvCodec struct{}
+
+	// csvColumn is a column of the CSV document holding a field of the list items.
+	csvColumn struct {
+		name  string
+		index []int
+	}
+)
+
+var (
+	// ErrCSVDecodeUnsupported is returned when a request body is decoded from CSV.
+	ErrCSVDecodeUnsupported = errors.New("CSV request bodies are not supported")
+
+	timeType = reflect.TypeOf(time.Time{})
+)
+
+// CSV returns the codec of the text/csv media type.
+// Only lists of structs are encoded: the header holds the JSON names of the fields,
+// every item is a row. Request bodies are not decoded.
+func CSV() Codec {
+	return csvCodec{}
+}
+
+func (csvCodec) MediaType() string {
+	return "text/csv"
+}
+
+func (csvCodec) Aliases() []string {
+	return nil
+}
+
+func (csvCodec) CanEncode(v any) bool {
+	if v == nil {
+		return false
+	}
+
+	t := reflect.TypeOf(v)
+	if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+		return false
+	}
+
+	t = t.Elem()
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return t.Kind() == reflect.Struct && t != timeType
+}
+
+func (csvCodec) Encode(w io.Writer, v any) error {
+	list := reflect.ValueOf(v)
+
+	itemType := list.Type().Elem()
+	for itemType.Kind() == reflect.Pointer {
+		itemType = itemType.Elem()
+	}
+
+	columns := csvColumns(itemType, nil)
+
+	writer := csv.NewWriter(w)
+	header := make([]string, 0, len(columns))
+
+	for _, column := range columns {
+		header = append(header, column.name)
+	}
+
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("encode CSV: %w", err)
+	}
+
+	for i := 0; i < list.Len(); i++ {
+		item := reflect.Indirect(list.Index(i))
+		record := make([]string, 0, len(columns))
+
+		for _, column := range columns {
+			record = append(record, csvValue(item, column.index))
+		}
+
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("encode CSV: %w", err)
+		}
+	}
+
+	writer.Flush()
+
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("encode CSV: %w", err)
+	}
+
+	return nil
+}
+
+func (csvCodec) CanDecode() bool {
+	return false
+}
+
+func (csvCodec) Decode(io.Reader, any) error {
+	return ErrCSVDecodeUnsupported
+}
+
+// csvColumns returns the columns of the struct fields in their declaration order,
+// named and filtered the way encoding/json does. Embedded structs are flattened.
+func csvColumns(t reflect.Type, parentIndex []int) []csvColumn {
+	var columns []csvColumn
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		index := append(append([]int{}, parentIndex...), i)
+		name := field.Name
+
+		if tag, ok := field.Tag.Lookup("json"); ok {
+			if tag == "-" {
+				continue
+			}
+
+			if tagName, _, _ := strings.Cut(tag, ","); tagName != "" {
+				name = tagName
+			} else if field.Anonymous && field.Type.Kind() == reflect.Struct {
+				columns = append(columns, csvColumns(field.Type, index)...)
+
+				continue
+			}
+		} else if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			columns = append(columns, csvColumns(field.Type, index)...)
+
+			continue
+		}
+
+		columns = append(columns, csvColumn{name: name, index: index})
+	}
+
+	return columns
+}
+
+// csvValue formats the field value: times in RFC 3339, nested values as JSON.
+// Strings starting like a formula are escaped, so the spreadsheets opening the document don't evaluate them.
+func csvValue(item reflect.Value, index []int) string {
+	field, err := item.FieldByIndexErr(index)
+	if err != nil {
+		return ""
+	}
+
+	for field.Kind() == reflect.Pointer {
+		if field.IsNil() {
+			return ""
+		}
+
+		field = field.Elem()
+	}
+
+	switch field.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		if t, ok := field.Interface().(time.Time); ok {
+			return t.Format(time.RFC3339Nano)
+		}
+
+		content, err := json.Marshal(field.Interface())
+		if err != nil {
+			return ""
+		}
+
+		return string(content)
+	case reflect.String:
+		return escapeFormula(field.String())
+	default:
+		return fmt.Sprint(field.Interface())
+	}
+}
+
+// escapeFormula prefixes the value starting with a formula character with a single quote,
+// which the spreadsheets take as the text marker.
+func escapeFormula(value string) string {
+	if value != "" && strings.ContainsRune(formulaPrefixes, rune(value[0])) {
+		return "'" + value
+	}
+
+	return value
+}
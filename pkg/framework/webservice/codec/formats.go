@@ -0,0 +1,219 @@
+package codec
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"gopkg.in/yaml.v3"
+)
+
+type (
+	jsonCodec        struct{}
+	messagePackCodec struct{}
+	xmlCodec         struct{}
+	yamlCodec        struct{}
+
+	// xmlList wraps list values, so they are encoded as a single XML document.
+	xmlList struct {
+		XMLName xml.Name `xml:"items"`
+		Items   any      `xml:"item"`
+	}
+)
+
+// JSON returns the codec of the application/json media type.
+func JSON() Codec {
+	return jsonCodec{}
+}
+
+func (jsonCodec) MediaType() string {
+	return "application/json"
+}
+
+func (jsonCodec) Aliases() []string {
+	return nil
+}
+
+func (jsonCodec) CanEncode(any) bool {
+	return true
+}
+
+func (jsonCodec) Encode(w io.Writer, v any) error {
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		return fmt.Errorf("encode JSON: %w", err)
+	}
+
+	return nil
+}
+
+func (jsonCodec) CanDecode() bool {
+	return true
+}
+
+func (jsonCodec) Decode(r io.Reader, v any) error {
+	if err := json.NewDecoder(r).Decode(v); err != nil {
+		return fmt.Errorf("decode JSON: %w", err)
+	}
+
+	return nil
+}
+
+// MessagePack returns the codec of the application/msgpack media type.
+// The values are keyed by their JSON names.
+func MessagePack() Codec {
+	return messagePackCodec{}
+}
+
+func (messagePackCodec) MediaType() string {
+	return "application/msgpack"
+}
+
+func (messagePackCodec) Aliases() []string {
+	return []string{"application/x-msgpack", "application/vnd.msgpack"}
+}
+
+func (messagePackCodec) CanEncode(any) bool {
+	return true
+}
+
+func (messagePackCodec) Encode(w io.Writer, v any) error {
+	encoder := msgpack.NewEncoder(w)
+	encoder.SetCustomStructTag("json")
+
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Errorf("encode MessagePack: %w", err)
+	}
+
+	return nil
+}
+
+func (messagePackCodec) CanDecode() bool {
+	return true
+}
+
+func (messagePackCodec) Decode(r io.Reader, v any) error {
+	decoder := msgpack.NewDecoder(r)
+	decoder.SetCustomStructTag("json")
+
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("decode MessagePack: %w", err)
+	}
+
+	return nil
+}
+
+// XML returns the codec of the application/xml media type.
+// Lists are wrapped into an "items" element.
+func XML() Codec {
+	return xmlCodec{}
+}
+
+func (xmlCodec) MediaType() string {
+	return "application/xml"
+}
+
+func (xmlCodec) Aliases() []string {
+	return []string{"text/xml"}
+}
+
+func (xmlCodec) CanEncode(v any) bool {
+	return v == nil || reflect.Indirect(reflect.ValueOf(v)).Kind() != reflect.Map
+}
+
+func (xmlCodec) Encode(w io.Writer, v any) error {
+	if v == nil {
+		return nil
+	}
+
+	if kind := reflect.Indirect(reflect.ValueOf(v)).Kind(); kind == reflect.Slice || kind == reflect.Array {
+		v = xmlList{Items: v}
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("encode XML: %w", err)
+	}
+
+	if err := xml.NewEncoder(w).Encode(v); err != nil {
+		return fmt.Errorf("encode XML: %w", err)
+	}
+
+	return nil
+}
+
+func (xmlCodec) CanDecode() bool {
+	return true
+}
+
+func (xmlCodec) Decode(r io.Reader, v any) error {
+	if err := xml.NewDecoder(r).Decode(v); err != nil {
+		return fmt.Errorf("decode XML: %w", err)
+	}
+
+	return nil
+}
+
+// YAML returns the codec of the application/yaml media type.
+// The values are converted through JSON, so they are keyed by their JSON names.
+func YAML() Codec {
+	return yamlCodec{}
+}
+
+func (yamlCodec) MediaType() string {
+	return "application/yaml"
+}
+
+func (yamlCodec) Aliases() []string {
+	return []string{"application/x-yaml", "text/yaml"}
+}
+
+func (yamlCodec) CanEncode(any) bool {
+	return true
+}
+
+func (yamlCodec) Encode(w io.Writer, v any) error {
+	content, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encode YAML: %w", err)
+	}
+
+	var document any
+	if err = json.Unmarshal(content, &document); err != nil {
+		return fmt.Errorf("encode YAML: %w", err)
+	}
+
+	encoder := yaml.NewEncoder(w)
+	if err = encoder.Encode(document); err != nil {
+		return fmt.Errorf("encode YAML: %w", err)
+	}
+
+	if err = encoder.Close(); err != nil {
+		return fmt.Errorf("encode YAML: %w", err)
+	}
+
+	return nil
+}
+
+func (yamlCodec) CanDecode() bool {
+	return true
+}
+
+func (yamlCodec) Decode(r io.Reader, v any) error {
+	var document any
+	if err := yaml.NewDecoder(r).Decode(&document); err != nil {
+		return fmt.Errorf("decode YAML: %w", err)
+	}
+
+	content, err := json.Marshal(document)
+	if err != nil {
+		return fmt.Errorf("decode YAML: %w", err)
+	}
+
+	if err = json.Unmarshal(content, v); err != nil {
+		return fmt.Errorf("decode YAML: %w", err)
+	}
+
+	return nil
+}
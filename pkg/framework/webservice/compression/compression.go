@@ -0,0 +1,57 @@
+package compression
+
+import (
+	"strings"
+
+	"solid-software.test-task/pkg/framework/config"
+)
+
+const (
+	defaultLevel = -1
+)
+
+type (
+	// Policy holds the response compression settings.
+	Policy struct {
+		Enabled bool
+		// MinSize is the size in bytes from which the responses are compressed.
+		MinSize int
+		// Level is the compression level, -1 means the default level of the encoding.
+		Level int
+		// Encodings are the offered content encodings in the order of preference, e.g. "br", "gzip" and "deflate".
+		Encodings []string
+	}
+)
+
+// NewPolicy reads the response compression policy from the "webService.compression" config section.
+func NewPolicy(cfg config.Config) Policy {
+	policy := Policy{
+		Enabled: cfg.GetBool("webService.compression.enabled"),
+		MinSize: int(cfg.GetSizeInBytes("webService.compression.minSize")),
+		Level:   defaultLevel,
+	}
+
+	if level := cfg.GetString("webService.compression.level"); level != "" {
+		policy.Level = cfg.GetInt("webService.compression.level")
+	}
+
+	for _, encoding := range cfg.GetStringSlice("webService.compression.encodings") {
+		policy.Encodings = append(policy.Encodings, strings.ToLower(strings.TrimSpace(encoding)))
+	}
+
+	return policy
+}
+
+// Compressible reports whether the responses of the media type benefit from the compression.
+// Already compressed media, e.g. images and archives, are not compressed again.
+func Compressible(contentType string) bool {
+	contentType = strings.ToLower(contentType)
+
+	for _, prefix := range []string{"image/", "video/", "audio/", "font/woff", "application/zip", "application/gzip"} {
+		if strings.HasPrefix(contentType, prefix) {
+			return !strings.HasPrefix(contentType, "image/svg")
+		}
+	}
+
+	return true
+}
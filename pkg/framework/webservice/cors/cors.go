@@ -0,0 +1,168 @@
+package cors
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"solid-software.test-task/pkg/framework/config"
+)
+
+type (
+	// Policy describes which cross-origin requests are allowed.
+	// The boolean fields are pointers, so an override leaving them nil keeps the values of the global policy.
+	Policy struct {
+		// Disabled turns off CORS headers for the endpoints.
+		Disabled *bool
+		// AllowedOrigins lists the allowed origins.
+		// "*" allows any origin, "https://*.example.com" allows any subdomain of example.com.
+		AllowedOrigins []string
+		// AllowedMethods lists the methods allowed in cross-origin requests.
+		AllowedMethods []string
+		// AllowedHeaders lists the request headers allowed in cross-origin requests. "*" allows any header.
+		AllowedHeaders []string
+		// ExposedHeaders lists the response headers exposed to the browser.
+		ExposedHeaders []string
+		// AllowCredentials allows cookies and authorization headers in cross-origin requests.
+		AllowCredentials *bool
+		// MaxAge is the time the preflight response may be cached by the browser.
+		MaxAge time.Duration
+	}
+)
+
+const (
+	wildcard = "*"
+)
+
+// NewPolicy reads the global CORS policy from the "webService.cors" config section.
+func NewPolicy(cfg config.Config) Policy {
+	return Policy{
+		Disabled:         Bool(!cfg.GetBool("webService.cors.enabled")),
+		AllowedOrigins:   cfg.GetStringSlice("webService.cors.allowedOrigins"),
+		AllowedMethods:   cfg.GetStringSlice("webService.cors.allowedMethods"),
+		AllowedHeaders:   cfg.GetStringSlice("webService.cors.allowedHeaders"),
+		ExposedHeaders:   cfg.GetStringSlice("webService.cors.exposedHeaders"),
+		AllowCredentials: Bool(cfg.GetBool("webService.cors.allowCredentials")),
+		MaxAge:           cfg.GetDuration("webService.cors.maxAge"),
+	}
+}
+
+// Bool returns a pointer to the value, e.g. to set the boolean fields of an override.
+func Bool(value bool) *bool {
+	return &value
+}
+
+// Merge returns a copy of the policy with the non-zero fields of the override applied.
+// The boolean fields of the override are applied when they are set, so a route may also
+// enable CORS disabled globally or disallow the credentials allowed globally.
+func (p Policy) Merge(override *Policy) Policy {
+	if override == nil {
+		return p
+	}
+
+	merged := p
+
+	if override.Disabled != nil {
+		merged.Disabled = override.Disabled
+	}
+
+	if override.AllowCredentials != nil {
+		merged.AllowCredentials = override.AllowCredentials
+	}
+
+	if len(override.AllowedOrigins) > 0 {
+		merged.AllowedOrigins = override.AllowedOrigins
+	}
+
+	if len(override.AllowedMethods) > 0 {
+		merged.AllowedMethods = override.AllowedMethods
+	}
+
+	if len(override.AllowedHeaders) > 0 {
+		merged.AllowedHeaders = override.AllowedHeaders
+	}
+
+	if len(override.ExposedHeaders) > 0 {
+		merged.ExposedHeaders = override.ExposedHeaders
+	}
+
+	if override.MaxAge > 0 {
+		merged.MaxAge = override.MaxAge
+	}
+
+	return merged
+}
+
+// IsDisabled reports whether the CORS headers are turned off.
+func (p Policy) IsDisabled() bool {
+	return p.Disabled != nil && *p.Disabled
+}
+
+// AllowsCredentials reports whether the cookies and the authorization headers are allowed in cross-origin requests.
+func (p Policy) AllowsCredentials() bool {
+	return p.AllowCredentials != nil && *p.AllowCredentials
+}
+
+// AllowsOrigin reports whether the origin is allowed by the policy.
+func (p Policy) AllowsOrigin(origin string) bool {
+	for _, allowed := range p.AllowedOrigins {
+		if matchOrigin(allowed, origin) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AllowsMethod reports whether the method is allowed by the policy.
+func (p Policy) AllowsMethod(method string) bool {
+	return containsFold(p.AllowedMethods, method)
+}
+
+// AllowsHeaders reports whether all the headers are allowed by the policy.
+func (p Policy) AllowsHeaders(headers []string) bool {
+	if containsFold(p.AllowedHeaders, wildcard) {
+		return true
+	}
+
+	for _, header := range headers {
+		if !containsFold(p.AllowedHeaders, header) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func matchOrigin(allowed, origin string) bool {
+	if allowed == wildcard || strings.EqualFold(allowed, origin) {
+		return true
+	}
+
+	if !strings.Contains(allowed, "://*.") {
+		return false
+	}
+
+	allowedURL, err := url.Parse(strings.Replace(allowed, "*.", "", 1))
+	if err != nil {
+		return false
+	}
+
+	originURL, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+
+	return strings.EqualFold(allowedURL.Scheme, originURL.Scheme) &&
+		strings.HasSuffix(strings.ToLower(originURL.Host), "."+strings.ToLower(allowedURL.Host))
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
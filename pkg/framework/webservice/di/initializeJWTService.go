@@ -11,6 +11,6 @@ import (
 )
 
 func InitializeJWTService() jwt.Service {
-	wire.Build(jwt.NewJWT, config.NewConfig)
+	wire.Build(jwt.NewJWT, config.NewJWTSection, config.NewConfig)
 	return nil
 }
@@ -22,6 +22,7 @@ func InitializeConfig() config.Config {
 
 func InitializeJWTService() jwt.Service {
 	configConfig := config.NewConfig()
-	jwtService := jwt.NewJWT(configConfig)
+	watched := config.NewJWTSection(configConfig)
+	jwtService := jwt.NewJWT(watched)
 	return jwtService
 }
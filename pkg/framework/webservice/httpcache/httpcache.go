@@ -0,0 +1,150 @@
+package httpcache
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+
+	"solid-software.test-task/pkg/framework/config"
+)
+
+const (
+	etagLength = 16
+)
+
+type (
+	// Revisioned is implemented by the resources which can be validated by their identity and modification time,
+	// e.g. entities with ID and UpdatedAt fields.
+	Revisioned interface {
+		Revision() (id uint, updatedAt time.Time)
+	}
+
+	// Validator holds the validators of a response.
+	Validator struct {
+		// ETag is the strong entity tag of the response, quoted.
+		ETag string
+		// LastModified is the modification time of the response.
+		LastModified time.Time
+	}
+
+	// Policy holds the Cache-Control header values of the GET responses.
+	Policy struct {
+		// Default is the Cache-Control value of the routes without a specific one.
+		Default string
+		// Routes maps route path templates (e.g. "/api/v1/users") to their Cache-Control values.
+		Routes map[string]string
+	}
+)
+
+// NewPolicy reads the Cache-Control policy from the "webService.cacheControl" config section.
+func NewPolicy(cfg config.Config) Policy {
+	policy := Policy{
+		Default: cfg.GetString("webService.cacheControl.default"),
+		Routes:  map[string]string{},
+	}
+
+	for path, value := range cfg.GetStringMap("webService.cacheControl.routes") {
+		policy.Routes[strings.ToLower(path)] = cast.ToString(value)
+	}
+
+	return policy
+}
+
+// WithDefault returns a copy of the policy with the default value replaced, if the value is set.
+func (p Policy) WithDefault(cacheControl string) Policy {
+	if cacheControl != "" {
+		p.Default = cacheControl
+	}
+
+	return p
+}
+
+// For returns the Cache-Control value of the route.
+func (p Policy) For(routePath string) string {
+	if cacheControl, ok := p.Routes[strings.ToLower(routePath)]; ok {
+		return cacheControl
+	}
+
+	return p.Default
+}
+
+// ValidatorOf returns the validator of the resource or the list of resources implementing Revisioned.
+// The variant, e.g. the media type, distinguishes the representations of the same resource.
+// It reports false if the value can't be validated.
+func ValidatorOf(v any, variant string) (Validator, bool) {
+	if v == nil {
+		return Validator{}, false
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(variant))
+
+	var lastModified time.Time
+
+	write := func(revisioned Revisioned) {
+		id, updatedAt := revisioned.Revision()
+		hash.Write(binary.BigEndian.AppendUint64(nil, uint64(id)))
+		hash.Write(binary.BigEndian.AppendUint64(nil, uint64(updatedAt.UnixNano())))
+
+		if updatedAt.After(lastModified) {
+			lastModified = updatedAt
+		}
+	}
+
+	if revisioned, ok := v.(Revisioned); ok {
+		write(revisioned)
+	} else {
+		list := reflect.ValueOf(v)
+		if list.Kind() != reflect.Slice && list.Kind() != reflect.Array {
+			return Validator{}, false
+		}
+
+		hash.Write(binary.BigEndian.AppendUint64(nil, uint64(list.Len())))
+
+		for i := 0; i < list.Len(); i++ {
+			revisioned, ok := list.Index(i).Interface().(Revisioned)
+			if !ok {
+				return Validator{}, false
+			}
+
+			write(revisioned)
+		}
+	}
+
+	return Validator{
+		ETag:         `"` + hex.EncodeToString(hash.Sum(nil)[:etagLength]) + `"`,
+		LastModified: lastModified,
+	}, true
+}
+
+// NotModified reports whether the request preconditions match the validator, so 304 Not Modified may be answered.
+// If-None-Match takes precedence over If-Modified-Since as defined by RFC 9110.
+func (v Validator) NotModified(header http.Header) bool {
+	if ifNoneMatch := header.Get("If-None-Match"); ifNoneMatch != "" {
+		for _, tag := range strings.Split(ifNoneMatch, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == "*" || tag == v.ETag {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if v.LastModified.IsZero() {
+		return false
+	}
+
+	ifModifiedSince, err := http.ParseTime(header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+
+	return !v.LastModified.Truncate(time.Second).After(ifModifiedSince)
+}
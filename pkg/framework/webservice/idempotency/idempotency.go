@@ -0,0 +1,111 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"solid-software.test-task/pkg/framework/config"
+)
+
+const (
+	defaultTTL         = 24 * time.Hour
+	defaultLockTimeout = time.Minute
+	defaultMaxKeyLen   = 255
+)
+
+type (
+	// Response is the stored response of a request, replayed for its retries.
+	Response struct {
+		StatusCode int
+		Header     http.Header
+		Body       []byte
+	}
+
+	// Lock describes the reservation of a key by the request being processed.
+	Lock struct {
+		// Key is the idempotency key scoped by the principal.
+		Key string
+		// Fingerprint identifies the request payload.
+		Fingerprint string
+		// LockedUntil is the time the reservation is considered abandoned at,
+		// so a retry may take it over after a crash.
+		LockedUntil time.Time
+		// ExpiresAt is the time the key may be reused at.
+		ExpiresAt time.Time
+	}
+
+	// Store keeps the idempotency keys with the responses of their requests.
+	// Implementations must be safe for concurrent use, also across replicas sharing the store.
+	Store interface {
+		// Acquire reserves the key for the request.
+		// It returns nil if the key was reserved, the stored response if the request was already completed,
+		// ErrKeyInUse if the request is still being processed,
+		// or ErrKeyMismatch if the key was used with a different request.
+		// Expired keys and abandoned reservations are taken over.
+		Acquire(ctx context.Context, lock Lock) (*Response, error)
+		// Complete stores the response of the request holding the key.
+		Complete(ctx context.Context, lock Lock, response Response) error
+		// Release removes the reservation of the key, so the request may be retried.
+		Release(ctx context.Context, lock Lock) error
+		// DeleteExpired removes the keys expired before the given time.
+		DeleteExpired(ctx context.Context, now time.Time) error
+	}
+
+	// Policy holds the idempotency settings.
+	Policy struct {
+		Enabled bool
+		// TTL is the period the keys and their responses are kept for.
+		TTL time.Duration
+		// LockTimeout is the period a request may hold a key for before its retry takes the key over.
+		LockTimeout time.Duration
+		// MaxKeyLength limits the length of the Idempotency-Key header.
+		MaxKeyLength int
+		// Secret keys the HMAC fingerprints of the request payloads,
+		// so the stored fingerprints don't reveal the payloads.
+		Secret []byte
+	}
+)
+
+var (
+	// ErrKeyInUse is returned when the idempotency key is held by a request in progress.
+	ErrKeyInUse = errors.New("a request with the same idempotency key is being processed")
+	// ErrKeyMismatch is returned when the idempotency key was used with a different request payload.
+	ErrKeyMismatch = errors.New("the idempotency key was used with a different request payload")
+)
+
+// NewPolicy reads the idempotency policy from the "webService.idempotency" config section.
+func NewPolicy(cfg config.Config) Policy {
+	policy := Policy{
+		Enabled:      cfg.GetBool("webService.idempotency.enabled"),
+		TTL:          cfg.GetDuration("webService.idempotency.ttl"),
+		LockTimeout:  cfg.GetDuration("webService.idempotency.lockTimeout"),
+		MaxKeyLength: cfg.GetInt("webService.idempotency.maxKeyLength"),
+		Secret:       []byte(cfg.GetString("webService.idempotency.secret")),
+	}
+
+	if policy.TTL <= 0 {
+		policy.TTL = defaultTTL
+	}
+
+	if policy.LockTimeout <= 0 {
+		policy.LockTimeout = defaultLockTimeout
+	}
+
+	if policy.MaxKeyLength <= 0 {
+		policy.MaxKeyLength = defaultMaxKeyLen
+	}
+
+	return policy
+}
+
+// NewLock returns the lock of the key for the request with the fingerprint, starting at the given time.
+func (p Policy) NewLock(key, fingerprint string, now time.Time) Lock {
+	return Lock{
+		Key:         key,
+		Fingerprint: fingerprint,
+		LockedUntil: now.Add(p.LockTimeout),
+		ExpiresAt:   now.Add(p.TTL),
+	}
+}
@@ -0,0 +1,32 @@
+package idempotency
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"solid-software.test-task/pkg/framework/logger"
+)
+
+const (
+	sweepInterval = 10 * time.Minute
+)
+
+// Sweeper returns a background worker which removes the expired keys from the store periodically.
+func Sweeper(store Store) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		ticker := time.NewTicker(sweepInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				if err := store.DeleteExpired(ctx, now); err != nil {
+					logger.Default().Error("delete expired idempotency keys", slog.Any("error", err))
+				}
+			}
+		}
+	}
+}
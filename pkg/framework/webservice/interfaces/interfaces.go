@@ -1,6 +1,9 @@
 package interfaces
 
 import (
+	"context"
+
+	"solid-software.test-task/pkg/framework/lifecycle"
 	"solid-software.test-task/pkg/framework/webservice/route"
 )
 
@@ -10,10 +13,18 @@ type (
 	WebService interface {
 		// RegisterEndpoints registers routes as endpoint or endpoint group to the web service.
 		RegisterEndpoints(routes ...route.Route)
+		// OnStart registers a hook executed before the web service starts listening.
+		// Hooks are executed in registration order.
+		OnStart(name string, hook lifecycle.Hook)
+		// OnStop registers a hook executed after the web service has drained in-flight requests.
+		// Hooks are executed in reverse registration order.
+		OnStop(name string, hook lifecycle.Hook)
+		// Go registers a background worker which is started and stopped together with the web service.
+		Go(name string, worker lifecycle.Worker)
 		// Run starts the web service.
-		// It will continue the execution until the application is stopped manually,
+		// It will continue the execution until the context is canceled,
 		// encounters an error or gets terminated.
-		// Returns an error if any error occurred during the service execution.
-		Run() error
+		// Returns an error if any error occurred during the service execution or shutdown.
+		Run(ctx context.Context) error
 	}
 )
@@ -0,0 +1,47 @@
+package jwt
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"solid-software.test-task/pkg/framework/logger"
+)
+
+const (
+	sweepInterval = 10 * time.Minute
+)
+
+type (
+	// Denylist keeps the revoked tokens until they expire, so they are rejected before their expiration.
+	Denylist interface {
+		// Revoke denies the token with the ID until it expires.
+		Revoke(ctx context.Context, tokenID string, expiresAt time.Time) error
+		// RevokeSubject denies the tokens issued to the subject before the given time.
+		// The entry is kept until the expiration time, when all those tokens have expired.
+		RevokeSubject(ctx context.Context, subject string, issuedBefore, expiresAt time.Time) error
+		// IsRevoked reports whether the token of the claim is denied by its ID or by its subject.
+		IsRevoked(ctx context.Context, claim *SampleClaim) (bool, error)
+		// DeleteExpired removes the entries expired before the given time.
+		DeleteExpired(ctx context.Context, now time.Time) error
+	}
+)
+
+// DenylistSweeper returns a background worker which removes the expired entries from the denylist periodically.
+func DenylistSweeper(denylist Denylist) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		ticker := time.NewTicker(sweepInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				if err := denylist.DeleteExpired(ctx, now); err != nil {
+					logger.Default().Error("delete expired denylist entries", slog.Any("error", err))
+				}
+			}
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/kataras/iris/v12"
 	irisJWT "github.com/kataras/iris/v12/middleware/jwt"
 
@@ -14,21 +15,36 @@ import (
 type (
 	// Service is an interface that has methods for working with JSON Web Tokens(JWT).
 	Service interface {
-		// GetToken creates a new token with the provided claim.
+		// GetToken creates a new token with the provided claim. Every token gets a unique ID (the "jti" claim),
+		// so it can be revoked.
 		GetToken(claim SampleClaim) ([]byte, error)
 		// VerifyTokenAndReturnClaim verifies the token and returns the claim if the token is valid.
 		VerifyTokenAndReturnClaim(ctx iris.Context) (*SampleClaim, error)
+		// ParseToken verifies the token given outside the Authorization header and returns its claim.
+		ParseToken(token string) (*SampleClaim, error)
 		// GetHandler returns a handler that verifies the incoming JWT tokens.
 		GetHandler() iris.Handler
 	}
 	// SampleClaim represents the claim information embedded in the JWT token.
 	SampleClaim struct {
+		// Subject is the ID of the authenticated user; it is empty for the anonymous tokens.
+		Subject  string `json:"sub,omitempty"`
 		Username string `json:"username"`
+		// Permissions are granted to the client, e.g. "users".
+		Permissions []string `json:"permissions,omitempty"`
+		// ID, IssuedAt and ExpiresAt are the standard claims of the verified token.
+		ID        string    `json:"-"`
+		IssuedAt  time.Time `json:"-"`
+		ExpiresAt time.Time `json:"-"`
 	}
 	jwt struct {
+		keys *config.Watched[keys]
+	}
+	// keys holds the signer and the verifier built from the "webService.jwt" config section.
+	keys struct {
 		signer   *irisJWT.Signer
 		verifier *irisJWT.Verifier
-		secret   string
+		verify   iris.Handler
 	}
 )
 
@@ -37,19 +53,30 @@ var (
 	ErrTokenInvalid = errors.New("token not found or invalid")
 	// ErrTokenExpired is returned when the JWT token has expired.
 	ErrTokenExpired = errors.New("token expired")
+	// ErrTokenRevoked is returned when the JWT token has been revoked before its expiration.
+	ErrTokenRevoked = errors.New("token revoked")
 )
 
-// NewJWT constructs a JWTService with the provided configurations.
-func NewJWT(conf config.Config) Service {
-	secret := conf.GetString("webService.jwt.secret")
-	tokenExpirationTime := time.Minute * time.Duration(conf.GetInt64("webService.jwt.tokenExpirationTimeInMinutes"))
-	signer := irisJWT.NewSigner(irisJWT.HS256, secret, tokenExpirationTime)
-	verifier := irisJWT.NewVerifier(irisJWT.HS256, secret)
-
+// NewJWT constructs a JWTService with the provided configuration section.
+// The secret and the token expiration follow the config reloads.
+func NewJWT(section *config.Watched[config.JWT]) Service {
 	return &jwt{
-		signer:   signer,
+		keys: config.Map(section, newKeys),
+	}
+}
+
+func newKeys(section config.JWT) keys {
+	tokenExpirationTime := time.Minute * time.Duration(section.TokenExpirationTimeInMinutes)
+	verifier := irisJWT.NewVerifier(irisJWT.HS256, section.Secret)
+
+	return keys{
+		signer:   irisJWT.NewSigner(irisJWT.HS256, section.Secret, tokenExpirationTime),
 		verifier: verifier,
-		secret:   secret,
+		verify: verifier.Verify(
+			func() any {
+				return &SampleClaim{}
+			},
+		),
 	}
 }
 
@@ -65,19 +92,41 @@ func (*jwt) VerifyTokenAndReturnClaim(irisCtx iris.Context) (*SampleClaim, error
 		return nil, ErrTokenExpired
 	}
 
+	return claimOf(verifiedToken)
+}
+
+// ParseToken verifies the token given outside the Authorization header and returns its claim.
+func (j *jwt) ParseToken(token string) (*SampleClaim, error) {
+	verifiedToken, err := j.keys.Load().verifier.VerifyToken([]byte(token))
+	if err != nil {
+		if errors.Is(err, irisJWT.ErrExpired) {
+			return nil, ErrTokenExpired
+		}
+
+		return nil, fmt.Errorf("%w: %w", ErrTokenInvalid, err)
+	}
+
+	return claimOf(verifiedToken)
+}
+
+func claimOf(verifiedToken *irisJWT.VerifiedToken) (*SampleClaim, error) {
 	var claim SampleClaim
 
-	err := verifiedToken.Claims(claim)
+	err := verifiedToken.Claims(&claim)
 	if err != nil {
 		return nil, fmt.Errorf("get SampleClaim: %w", err)
 	}
 
+	claim.ID = verifiedToken.StandardClaims.ID
+	claim.IssuedAt = time.Unix(verifiedToken.StandardClaims.IssuedAt, 0)
+	claim.ExpiresAt = verifiedToken.StandardClaims.ExpiresAt()
+
 	return &claim, nil
 }
 
 // GetToken creates a new token with the provided claim.
 func (j *jwt) GetToken(claim SampleClaim) ([]byte, error) {
-	tokenBytes, err := j.signer.Sign(&claim)
+	tokenBytes, err := j.keys.Load().signer.Sign(&claim, irisJWT.ID(uuid.NewString()))
 	if err != nil {
 		return nil, fmt.Errorf("sign claim: %w", err)
 	}
@@ -87,9 +136,7 @@ func (j *jwt) GetToken(claim SampleClaim) ([]byte, error) {
 
 // GetHandler returns a handler that verifies the incoming JWT tokens.
 func (j *jwt) GetHandler() iris.Handler {
-	return j.verifier.Verify(
-		func() any {
-			return &SampleClaim{}
-		},
-	)
+	return func(irisCtx iris.Context) {
+		j.keys.Load().verify(irisCtx)
+	}
 }
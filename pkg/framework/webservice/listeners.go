@@ -0,0 +1,290 @@
+package webservice
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/kataras/iris/v12/core/host"
+	"github.com/spf13/cast"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+
+	"solid-software.test-task/pkg/framework/ctxutils"
+	"solid-software.test-task/pkg/framework/logger"
+	"solid-software.test-task/pkg/framework/webservice/tlsconfig"
+)
+
+type (
+	server struct {
+		host     *host.Supervisor
+		listener net.Listener
+	}
+
+	// listenerConfig describes a listener serving the API.
+	listenerConfig struct {
+		Name string
+		// Network is "tcp" or "unix".
+		Network string
+		// Address is the host:port of a TCP listener or the socket path of a Unix listener.
+		Address string
+		// SocketMode is the file mode of the Unix socket.
+		SocketMode os.FileMode
+		// TLS serves HTTPS with the webService.tls configuration.
+		TLS bool
+		// H2C serves HTTP/2 without TLS next to HTTP/1.1.
+		H2C bool
+		// TrustForwardedFor takes the client address from the X-Forwarded-For header,
+		// for the listeners reached only through a trusted proxy, e.g. a sidecar on a Unix socket.
+		TrustForwardedFor bool
+	}
+)
+
+const (
+	httpsDefaultPort  = 443
+	defaultSocketMode = 0o660
+	networkTCP        = "tcp"
+	networkUnix       = "unix"
+)
+
+var (
+	// ErrInvalidListener is returned when a listener is misconfigured.
+	ErrInvalidListener = errors.New("invalid listener")
+)
+
+// prepareTLS builds the TLS configuration if TLS is enabled
+// and registers the certificate reloader as a background worker.
+func (w *webService) prepareTLS() error {
+	if !tlsconfig.Enabled(w.config) {
+		return nil
+	}
+
+	tlsConfig, reloader, err := tlsconfig.New(w.config)
+	if err != nil {
+		return fmt.Errorf("configure TLS: %w", err)
+	}
+
+	w.tlsConfig = tlsConfig
+	w.lifecycle.Go("tls certificate reloader", reloader.Watch)
+
+	return nil
+}
+
+// createServers opens the listeners and creates the hosts serving them.
+// The hosts are registered in the application, so they are shut down together with it.
+func (w *webService) createServers() ([]server, error) {
+	listenerConfigs, err := w.listenerConfigs()
+	if err != nil {
+		return nil, err
+	}
+
+	servers := make([]server, 0, len(listenerConfigs))
+
+	for _, listenerConfig := range listenerConfigs {
+		apiServer, err := w.createAPIServer(listenerConfig)
+		if err != nil {
+			closeServers(servers)
+
+			return nil, err
+		}
+
+		servers = append(servers, *apiServer)
+	}
+
+	if w.tlsConfig != nil && w.config.GetBool("webService.tls.redirectHTTP.enabled") {
+		redirect, err := w.createRedirectServer()
+		if err != nil {
+			closeServers(servers)
+
+			return nil, err
+		}
+
+		servers = append(servers, *redirect)
+	}
+
+	metricsServer, err := w.createMetricsServer()
+	if err != nil {
+		closeServers(servers)
+
+		return nil, err
+	}
+
+	if metricsServer != nil {
+		servers = append(servers, *metricsServer)
+	}
+
+	adminServer, err := w.createAdminServer()
+	if err != nil {
+		closeServers(servers)
+
+		return nil, err
+	}
+
+	if adminServer != nil {
+		servers = append(servers, *adminServer)
+	}
+
+	return servers, nil
+}
+
+// listenerConfigs returns the API listeners of the "webService.listeners" section, keyed by their names.
+// Without it the API is served on "webService.host" and "webService.port", with TLS if it is enabled.
+func (w *webService) listenerConfigs() ([]listenerConfig, error) {
+	listeners := w.config.GetStringMap("webService.listeners")
+	if len(listeners) == 0 {
+		return []listenerConfig{{
+			Name:    "default",
+			Network: networkTCP,
+			Address: fmt.Sprintf("%s:%d", w.config.GetString("webService.host"), w.config.GetUint("webService.port")),
+			TLS:     w.tlsConfig != nil,
+			H2C:     w.config.GetBool("webService.h2c"),
+		}}, nil
+	}
+
+	configs := make([]listenerConfig, 0, len(listeners))
+
+	for name, value := range listeners {
+		settings := cast.ToStringMap(value)
+
+		listenerConfig := listenerConfig{
+			Name:              name,
+			Network:           cast.ToString(settings["network"]),
+			Address:           cast.ToString(settings["address"]),
+			SocketMode:        defaultSocketMode,
+			TLS:               cast.ToBool(settings["tls"]),
+			H2C:               cast.ToBool(settings["h2c"]),
+			TrustForwardedFor: cast.ToBool(settings["trustforwardedfor"]),
+		}
+
+		if listenerConfig.Network == "" {
+			listenerConfig.Network = networkTCP
+		}
+
+		if mode := cast.ToString(settings["socketmode"]); mode != "" {
+			parsed, err := strconv.ParseUint(mode, 8, 32)
+			if err != nil {
+				return nil, fmt.Errorf("%w %q: socket mode %q: %w", ErrInvalidListener, name, mode, err)
+			}
+
+			listenerConfig.SocketMode = os.FileMode(parsed)
+		}
+
+		if err := w.validateListener(listenerConfig); err != nil {
+			return nil, err
+		}
+
+		configs = append(configs, listenerConfig)
+	}
+
+	sort.Slice(configs, func(i, j int) bool {
+		return configs[i].Name < configs[j].Name
+	})
+
+	return configs, nil
+}
+
+func (w *webService) validateListener(listenerConfig listenerConfig) error {
+	switch {
+	case listenerConfig.Network != networkTCP && listenerConfig.Network != networkUnix:
+		return fmt.Errorf("%w %q: unknown network %q", ErrInvalidListener, listenerConfig.Name, listenerConfig.Network)
+	case listenerConfig.Address == "":
+		return fmt.Errorf("%w %q: the address is not set", ErrInvalidListener, listenerConfig.Name)
+	case listenerConfig.TLS && w.tlsConfig == nil:
+		return fmt.Errorf("%w %q: TLS is not enabled in webService.tls", ErrInvalidListener, listenerConfig.Name)
+	case listenerConfig.TLS && listenerConfig.H2C:
+		return fmt.Errorf("%w %q: h2c is served without TLS only", ErrInvalidListener, listenerConfig.Name)
+	}
+
+	return nil
+}
+
+// createAPIServer opens the listener and creates the host serving the API on it.
+func (w *webService) createAPIServer(listenerConfig listenerConfig) (*server, error) {
+	var (
+		listener net.Listener
+		err      error
+	)
+
+	if listenerConfig.Network == networkUnix {
+		listener, err = listenUnix(listenerConfig.Address, listenerConfig.SocketMode)
+	} else {
+		listener, err = net.Listen(networkTCP, listenerConfig.Address)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("listen %q: %w", listenerConfig.Address, err)
+	}
+
+	httpServer := &http.Server{Addr: listenerConfig.Address}
+
+	if listenerConfig.TrustForwardedFor {
+		httpServer.ConnContext = func(ctx context.Context, _ net.Conn) context.Context {
+			return context.WithValue(ctx, ctxutils.TrustedProxyContextKey, true)
+		}
+	}
+
+	switch {
+	case listenerConfig.TLS:
+		httpServer.TLSConfig = w.tlsConfig
+		listener = tls.NewListener(listener, w.tlsConfig)
+	case listenerConfig.H2C:
+		httpServer.Handler = h2c.NewHandler(w.application.Router, &http2.Server{})
+	}
+
+	logger.Default().Info(
+		"API listener", slog.String("name", listenerConfig.Name), slog.String("network", listenerConfig.Network),
+		slog.String("listen", listenerConfig.Address), slog.Bool("tls", listenerConfig.TLS),
+		slog.Bool("h2c", listenerConfig.H2C), slog.Bool("trust_forwarded_for", listenerConfig.TrustForwardedFor),
+	)
+
+	return &server{host: w.application.NewHost(httpServer), listener: listener}, nil
+}
+
+func (w *webService) createRedirectServer() (*server, error) {
+	addr := fmt.Sprintf(
+		"%s:%d", w.config.GetString("webService.host"), w.config.GetUint("webService.tls.redirectHTTP.port"),
+	)
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("listen %q: %w", addr, err)
+	}
+
+	redirectServer := &http.Server{
+		Addr:    addr,
+		Handler: redirectToHTTPS(w.config.GetUint("webService.port")),
+	}
+
+	return &server{host: w.application.NewHost(redirectServer), listener: listener}, nil
+}
+
+// redirectToHTTPS returns a handler that permanently redirects every request to the HTTPS listener.
+func redirectToHTTPS(httpsPort uint) http.Handler {
+	return http.HandlerFunc(
+		func(writer http.ResponseWriter, request *http.Request) {
+			hostname := request.Host
+			if h, _, err := net.SplitHostPort(request.Host); err == nil {
+				hostname = h
+			}
+
+			if httpsPort != httpsDefaultPort {
+				hostname = net.JoinHostPort(hostname, strconv.FormatUint(uint64(httpsPort), 10))
+			}
+
+			http.Redirect(writer, request, "https://"+hostname+request.URL.RequestURI(), http.StatusPermanentRedirect)
+		},
+	)
+}
+
+func closeServers(servers []server) {
+	for _, s := range servers {
+		_ = s.listener.Close()
+	}
+}
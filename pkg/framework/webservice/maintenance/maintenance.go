@@ -0,0 +1,93 @@
+package maintenance
+
+import (
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"solid-software.test-task/pkg/framework/config"
+	"solid-software.test-task/pkg/framework/logger"
+)
+
+const (
+	defaultRetryAfter = 5 * time.Minute
+	defaultMessage    = "the service is in maintenance, only reading is available"
+)
+
+type (
+	// Status is the maintenance mode state.
+	Status struct {
+		// Enabled reports whether the service is read-only.
+		Enabled bool
+		// Message explains the maintenance to the clients.
+		Message string
+		// RetryAfter is the expected duration of the maintenance, sent in the Retry-After header.
+		RetryAfter time.Duration
+		// Since is the time the maintenance mode was switched on or off.
+		Since time.Time
+	}
+
+	mode struct {
+		mu     sync.RWMutex
+		status Status
+	}
+)
+
+var (
+	// ErrReadOnly is returned for the mutating requests while the maintenance mode is on.
+	ErrReadOnly = errors.New("the service is read-only during maintenance")
+
+	_mode = &mode{status: Status{Since: time.Now()}} //nolint:gochecknoglobals
+)
+
+// Current returns the maintenance mode state.
+func Current() Status {
+	_mode.mu.RLock()
+	defer _mode.mu.RUnlock()
+
+	return _mode.status
+}
+
+// Set switches the maintenance mode on or off. The zero message and retry-after keep the defaults.
+func Set(enabled bool, message string, retryAfter time.Duration) Status {
+	_mode.mu.Lock()
+	defer _mode.mu.Unlock()
+
+	return _mode.set(enabled, message, retryAfter)
+}
+
+// Configure applies the "maintenance" config section and applies it again whenever a config reload changes it.
+// So the mode switched through the admin API or a signal is kept unless the config is changed too.
+func Configure(cfg config.Config) {
+	configure(cfg)
+	cfg.OnChange("maintenance", func() {
+		configure(cfg)
+	})
+}
+
+func configure(cfg config.Config) {
+	Set(cfg.GetBool("maintenance.enabled"), cfg.GetString("maintenance.message"), cfg.GetDuration("maintenance.retryAfter"))
+}
+
+func (m *mode) set(enabled bool, message string, retryAfter time.Duration) Status {
+	if message == "" {
+		message = defaultMessage
+	}
+
+	if retryAfter <= 0 {
+		retryAfter = defaultRetryAfter
+	}
+
+	if m.status.Enabled != enabled {
+		m.status.Since = time.Now()
+
+		logger.Default().Warn("maintenance mode switched", slog.Bool("enabled", enabled), slog.String("message", message))
+	}
+
+	m.status.Enabled = enabled
+	m.status.Message = message
+	m.status.RetryAfter = retryAfter
+
+	return m.status
+}
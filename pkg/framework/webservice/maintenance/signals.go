@@ -0,0 +1,27 @@
+package maintenance
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// SignalWatcher is a background worker switching the maintenance mode by the signals:
+// SIGUSR1 switches it on and SIGUSR2 switches it off.
+func SignalWatcher(ctx context.Context) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGUSR1, syscall.SIGUSR2)
+
+	defer signal.Stop(signals)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case received := <-signals:
+			status := Current()
+			Set(received == syscall.SIGUSR1, status.Message, status.RetryAfter)
+		}
+	}
+}
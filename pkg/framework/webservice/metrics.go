@@ -0,0 +1,64 @@
+package webservice
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/kataras/iris/v12"
+
+	"solid-software.test-task/pkg/framework/metrics"
+	"solid-software.test-task/pkg/framework/webservice/admin"
+)
+
+const (
+	defaultMetricsPath = "/metrics"
+)
+
+// registerMetricsEndpoint serves the metrics on the API listener, protected by the "metrics.token" bearer credential,
+// if they are enabled and configured to be served on the API listener.
+func (w *webService) registerMetricsEndpoint() {
+	if !w.config.GetBool("metrics.enabled") || w.config.GetUint("metrics.port") != 0 {
+		return
+	}
+
+	w.application.Get(w.metricsPath(), iris.FromStd(w.metricsHandler()))
+}
+
+// createMetricsServer creates the internal listener serving only the metrics.
+// It returns nil if the metrics are disabled or served on the API listener.
+func (w *webService) createMetricsServer() (*server, error) {
+	port := w.config.GetUint("metrics.port")
+	if !w.config.GetBool("metrics.enabled") || port == 0 {
+		return nil, nil //nolint:nilnil
+	}
+
+	addr := fmt.Sprintf("%s:%d", w.config.GetString("metrics.host"), port)
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("listen %q: %w", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(w.metricsPath(), w.metricsHandler())
+
+	return &server{host: w.application.NewHost(&http.Server{Addr: addr, Handler: mux}), listener: listener}, nil
+}
+
+// metricsHandler serves the metrics, requiring the "metrics.token" bearer credential if it is set.
+func (w *webService) metricsHandler() http.Handler {
+	if token := w.config.GetString("metrics.token"); token != "" {
+		return admin.Authenticate("metrics", token, metrics.Handler())
+	}
+
+	return metrics.Handler()
+}
+
+func (w *webService) metricsPath() string {
+	if path := w.config.GetString("metrics.path"); path != "" {
+		return path
+	}
+
+	return defaultMetricsPath
+}
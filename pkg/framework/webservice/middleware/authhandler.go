@@ -1,8 +1,8 @@
 package middleware
 
 import (
-	"context"
 	"errors"
+	"fmt"
 
 	"github.com/kataras/iris/v12"
 
@@ -12,14 +12,19 @@ import (
 )
 
 // AuthHandler returns Iris middleware handler that authorizes user by JWT token.
-// If the token is valid it proceeds to set the context with username.
+// If the token is valid it proceeds to set the context with username and continues the chain.
 // If the token is invalid an Unauthorized HTTP error is returned to the client.
-// It uses jwt.Service to authenticate and authorize the client.
-func AuthHandler(service jwt.Service) iris.Handler {
+// It uses jwt.Service to authenticate and authorize the client, and rejects the tokens revoked by the denylist.
+func AuthHandler(service jwt.Service, denylist jwt.Denylist) iris.Handler {
 	return func(irisCtx iris.Context) {
 		sampleClaim, err := service.VerifyTokenAndReturnClaim(irisCtx)
+		if err == nil {
+			err = checkRevoked(irisCtx, denylist, sampleClaim)
+		}
+
 		if err != nil {
-			if errors.Is(err, jwt.ErrTokenExpired) || errors.Is(err, jwt.ErrTokenInvalid) {
+			if errors.Is(err, jwt.ErrTokenExpired) || errors.Is(err, jwt.ErrTokenInvalid) ||
+				errors.Is(err, jwt.ErrTokenRevoked) {
 				api.HandleError(irisCtx, iris.StatusUnauthorized, err)
 			} else {
 				api.HandleError(irisCtx, iris.StatusInternalServerError, err)
@@ -28,11 +33,27 @@ func AuthHandler(service jwt.Service) iris.Handler {
 			return
 		}
 
+		setRequestContextValue(irisCtx, ctxutils.PermissionsContextKey, sampleClaim.Permissions)
+		setRequestContextValue(irisCtx, ctxutils.SubjectContextKey, sampleClaim.Subject)
 		setContextWithUsername(irisCtx, sampleClaim.Username)
+		irisCtx.Next()
+	}
+}
+
+func checkRevoked(irisCtx iris.Context, denylist jwt.Denylist, claim *jwt.SampleClaim) error {
+	revoked, err := denylist.IsRevoked(irisCtx.Request().Context(), claim)
+	if err != nil {
+		return fmt.Errorf("check token denylist: %w", err)
+	}
+
+	if revoked {
+		return jwt.ErrTokenRevoked
 	}
+
+	return nil
 }
 
 func setContextWithUsername(irisCtx iris.Context, username string) {
-	ctx := context.WithValue(irisCtx.Request().Context(), ctxutils.UsernameContextKey, username)
-	irisCtx.Values().Set(string(ctxutils.AppContextKey), ctx)
+	setRequestContextValue(irisCtx, ctxutils.UsernameContextKey, username)
+	irisCtx.Values().Set(string(ctxutils.AppContextKey), irisCtx.Request().Context())
 }
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"github.com/kataras/iris/v12"
+
+	"solid-software.test-task/pkg/framework/webservice/httpcache"
+)
+
+const (
+	headerCacheControl = "Cache-Control"
+)
+
+// CacheControlHandler returns a middleware handler that sets the Cache-Control header of the GET and HEAD responses
+// according to the policy of the route. The endpoint handlers may override it.
+func CacheControlHandler(policy httpcache.Policy) iris.Handler {
+	return func(irisCtx iris.Context) {
+		if method := irisCtx.Method(); method == iris.MethodGet || method == iris.MethodHead {
+			if cacheControl := policy.For(irisCtx.GetCurrentRoute().Path()); cacheControl != "" {
+				irisCtx.Header(headerCacheControl, cacheControl)
+			}
+		}
+
+		irisCtx.Next()
+	}
+}
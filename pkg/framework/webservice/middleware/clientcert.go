@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"github.com/kataras/iris/v12"
+
+	"solid-software.test-task/pkg/framework/ctxutils"
+)
+
+// ClientCertificateHandler returns a middleware handler that exposes the subject
+// of the verified TLS client certificate to the handlers.
+// The subject is stored in the request context under ctxutils.ClientSubjectContextKey.
+// Requests without a verified client certificate are passed through unchanged.
+func ClientCertificateHandler() iris.Handler {
+	return func(irisCtx iris.Context) {
+		connState := irisCtx.Request().TLS
+		if connState != nil && len(connState.VerifiedChains) > 0 && len(connState.VerifiedChains[0]) > 0 {
+			setRequestContextValue(irisCtx, ctxutils.ClientSubjectContextKey, connState.VerifiedChains[0][0].Subject.String())
+		}
+
+		irisCtx.Next()
+	}
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/kataras/iris/v12"
+	irisContext "github.com/kataras/iris/v12/context"
+
+	"solid-software.test-task/pkg/framework/logger"
+	"solid-software.test-task/pkg/framework/webservice/compression"
+)
+
+const (
+	headerETag = "ETag"
+)
+
+// CompressionHandler returns a middleware handler that compresses the responses
+// with the best content encoding accepted by the client.
+// The response is recorded, so only the bodies of at least the configured size are compressed.
+// Strong entity tags of the compressed responses are weakened, as the compressed representation differs byte-wise.
+func CompressionHandler(policy compression.Policy) iris.Handler {
+	return func(irisCtx iris.Context) {
+		if !policy.Enabled || irisCtx.Method() == iris.MethodHead || irisCtx.GetHeader("Accept-Encoding") == "" {
+			irisCtx.Next()
+
+			return
+		}
+
+		irisCtx.Record()
+		irisCtx.Next()
+
+		recorder := irisCtx.Recorder()
+		header := recorder.Header()
+		body := recorder.Body()
+
+		header.Add(irisContext.VaryHeaderKey, irisContext.AcceptEncodingHeaderKey)
+
+		if len(body) < policy.MinSize || header.Get(headerContentEncoding) != "" ||
+			!compression.Compressible(header.Get(headerContentType)) {
+			return
+		}
+
+		encoding, err := irisContext.GetEncoding(irisCtx.Request(), policy.Encodings)
+		if err != nil || encoding == irisContext.IDENTITY {
+			return
+		}
+
+		var compressed bytes.Buffer
+
+		if err = compress(&compressed, body, encoding, policy.Level); err != nil {
+			logger.FromContext(irisCtx.Request().Context()).Error("compress response", slog.Any("error", err))
+
+			return
+		}
+
+		recorder.SetBody(compressed.Bytes())
+		header.Set(headerContentEncoding, encoding)
+		header.Del("Content-Length")
+
+		if etag := header.Get(headerETag); etag != "" && !strings.HasPrefix(etag, "W/") {
+			header.Set(headerETag, "W/"+etag)
+		}
+	}
+}
+
+func compress(buffer *bytes.Buffer, body []byte, encoding string, level int) error {
+	writer, err := irisContext.NewCompressWriter(buffer, encoding, level)
+	if err != nil {
+		return fmt.Errorf("create %s writer: %w", encoding, err)
+	}
+
+	if _, err = writer.Write(body); err != nil {
+		return fmt.Errorf("write %s body: %w", encoding, err)
+	}
+
+	if err = writer.Close(); err != nil {
+		return fmt.Errorf("close %s writer: %w", encoding, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/kataras/iris/v12"
+
+	"solid-software.test-task/pkg/framework/ctxutils"
+)
+
+// setRequestContextValue stores the value in the request context,
+// so it is available to the handlers through the injected context.Context.
+func setRequestContextValue(irisCtx iris.Context, key, value any) {
+	ctx := context.WithValue(irisCtx.Request().Context(), key, value)
+	irisCtx.ResetRequest(irisCtx.Request().WithContext(ctx))
+}
+
+// principalKey identifies the client of the request by the authenticated username,
+// falling back to the client IP.
+func principalKey(irisCtx iris.Context) string {
+	if username, ok := ctxutils.Username(irisCtx.Request().Context()); ok && username != "" {
+		return "user:" + username
+	}
+
+	return "ip:" + irisCtx.RemoteAddr()
+}
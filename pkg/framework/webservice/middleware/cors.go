@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/kataras/iris/v12"
+
+	"solid-software.test-task/pkg/framework/webservice/cors"
+)
+
+const (
+	headerOrigin                        = "Origin"
+	headerVary                          = "Vary"
+	headerAccessControlRequestMethod    = "Access-Control-Request-Method"
+	headerAccessControlRequestHeaders   = "Access-Control-Request-Headers"
+	headerAccessControlAllowOrigin      = "Access-Control-Allow-Origin"
+	headerAccessControlAllowMethods     = "Access-Control-Allow-Methods"
+	headerAccessControlAllowHeaders     = "Access-Control-Allow-Headers"
+	headerAccessControlAllowCredentials = "Access-Control-Allow-Credentials"
+	headerAccessControlExposeHeaders    = "Access-Control-Expose-Headers"
+	headerAccessControlMaxAge           = "Access-Control-Max-Age"
+)
+
+// CORSHandler returns a middleware handler that applies the current CORS policy.
+// Preflight requests are answered by the handler itself and never reach the endpoint handlers.
+// OPTIONS requests are always answered with 204 No Content,
+// even when the policy is disabled or the origin is not allowed.
+func CORSHandler(currentPolicy func() cors.Policy) iris.Handler {
+	return func(irisCtx iris.Context) {
+		policy := currentPolicy()
+		origin := irisCtx.GetHeader(headerOrigin)
+		isOptions := irisCtx.Method() == iris.MethodOptions
+
+		if policy.IsDisabled() || origin == "" {
+			if isOptions {
+				irisCtx.StopWithStatus(iris.StatusNoContent)
+			} else {
+				irisCtx.Next()
+			}
+
+			return
+		}
+
+		irisCtx.Header(headerVary, headerOrigin)
+
+		if isOptions {
+			handlePreflight(irisCtx, policy, origin)
+
+			return
+		}
+
+		if policy.AllowsOrigin(origin) {
+			setAllowOriginHeaders(irisCtx, policy, origin)
+
+			if len(policy.ExposedHeaders) > 0 {
+				irisCtx.Header(headerAccessControlExposeHeaders, strings.Join(policy.ExposedHeaders, ", "))
+			}
+		}
+
+		irisCtx.Next()
+	}
+}
+
+func handlePreflight(irisCtx iris.Context, policy cors.Policy, origin string) {
+	defer irisCtx.StopWithStatus(iris.StatusNoContent)
+
+	irisCtx.Header(headerVary, headerAccessControlRequestMethod)
+	irisCtx.Header(headerVary, headerAccessControlRequestHeaders)
+
+	requestMethod := irisCtx.GetHeader(headerAccessControlRequestMethod)
+	requestHeaders := parseHeaderList(irisCtx.GetHeader(headerAccessControlRequestHeaders))
+
+	if requestMethod == "" || !policy.AllowsOrigin(origin) ||
+		!policy.AllowsMethod(requestMethod) || !policy.AllowsHeaders(requestHeaders) {
+		return
+	}
+
+	setAllowOriginHeaders(irisCtx, policy, origin)
+	irisCtx.Header(headerAccessControlAllowMethods, strings.Join(policy.AllowedMethods, ", "))
+
+	if len(requestHeaders) > 0 {
+		irisCtx.Header(headerAccessControlAllowHeaders, strings.Join(requestHeaders, ", "))
+	}
+
+	if policy.MaxAge > 0 {
+		irisCtx.Header(headerAccessControlMaxAge, strconv.Itoa(int(policy.MaxAge.Seconds())))
+	}
+}
+
+// setAllowOriginHeaders echoes the request origin instead of "*",
+// so the response stays valid when credentials are allowed.
+func setAllowOriginHeaders(irisCtx iris.Context, policy cors.Policy, origin string) {
+	irisCtx.Header(headerAccessControlAllowOrigin, origin)
+
+	if policy.AllowsCredentials() {
+		irisCtx.Header(headerAccessControlAllowCredentials, "true")
+	}
+}
+
+func parseHeaderList(value string) []string {
+	if value == "" {
+		return nil
+	}
+
+	headers := strings.Split(value, ",")
+	for i := range headers {
+		headers[i] = strings.TrimSpace(headers[i])
+	}
+
+	return headers
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/kataras/iris/v12"
+
+	"solid-software.test-task/pkg/framework/webservice/versioning"
+)
+
+const (
+	headerDeprecation = "Deprecation"
+	headerSunset      = "Sunset"
+	headerLink        = "Link"
+)
+
+// DeprecationHandler returns a middleware handler that signals the deprecation of the API version
+// with the Deprecation (RFC 9745) and Sunset (RFC 8594) headers.
+// It returns nil if the version is not deprecated.
+func DeprecationHandler(version versioning.Version) iris.Handler {
+	if !version.Deprecated {
+		return nil
+	}
+
+	deprecation := fmt.Sprintf("@%d", version.Deprecation.Unix())
+
+	var sunset string
+	if !version.Sunset.IsZero() {
+		sunset = version.Sunset.UTC().Format(http.TimeFormat)
+	}
+
+	var link string
+	if version.Link != "" {
+		link = fmt.Sprintf("<%s>; rel=\"deprecation\"", version.Link)
+	}
+
+	return func(irisCtx iris.Context) {
+		irisCtx.Header(headerDeprecation, deprecation)
+
+		if sunset != "" {
+			irisCtx.Header(headerSunset, sunset)
+		}
+
+		if link != "" {
+			irisCtx.ResponseWriter().Header().Add(headerLink, link)
+		}
+
+		irisCtx.Next()
+	}
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net"
+	"strings"
+
+	"github.com/kataras/iris/v12"
+
+	"solid-software.test-task/pkg/framework/ctxutils"
+)
+
+const (
+	headerForwardedFor = "X-Forwarded-For"
+)
+
+// ForwardedForHandler returns a middleware handler that takes the client address of the requests
+// coming through a listener behind a trusted proxy from the X-Forwarded-For header, so the access log,
+// the rate limits and the idempotency keys tell the clients apart, e.g. on a Unix socket.
+// The last address of the header is taken, as it is the one appended by the trusted proxy.
+// The requests of the other listeners and those without a valid address are passed through unchanged.
+func ForwardedForHandler() iris.Handler {
+	return func(irisCtx iris.Context) {
+		request := irisCtx.Request()
+
+		if ctxutils.IsTrustedProxy(request.Context()) {
+			if ip := lastForwardedFor(request.Header.Values(headerForwardedFor)); ip != nil {
+				request.RemoteAddr = net.JoinHostPort(ip.String(), "0")
+			}
+		}
+
+		irisCtx.Next()
+	}
+}
+
+func lastForwardedFor(values []string) net.IP {
+	if len(values) == 0 {
+		return nil
+	}
+
+	addresses := strings.Split(values[len(values)-1], ",")
+
+	return net.ParseIP(strings.TrimSpace(addresses[len(addresses)-1]))
+}
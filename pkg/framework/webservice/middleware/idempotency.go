@@ -0,0 +1,200 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kataras/iris/v12"
+
+	"solid-software.test-task/pkg/framework/logger"
+	"solid-software.test-task/pkg/framework/webservice/idempotency"
+	"solid-software.test-task/pkg/infra/api"
+)
+
+const (
+	headerIdempotencyKey     = "Idempotency-Key"
+	headerIdempotentReplayed = "Idempotent-Replayed"
+	headerContentType        = "Content-Type"
+	directiveNoStore         = "no-store"
+)
+
+var (
+	// ErrIdempotencyKeyTooLong is returned when the Idempotency-Key header exceeds the configured length.
+	ErrIdempotencyKeyTooLong = errors.New("the idempotency key is too long")
+
+	// notReplayedHeaders are the response headers describing the original request rather than the response.
+	notReplayedHeaders = []string{ //nolint:gochecknoglobals
+		HeaderRequestID, headerRateLimitLimit, headerRateLimitRemaining, headerRateLimitReset, headerRetryAfter,
+		"Date", "Content-Length",
+	}
+)
+
+// IdempotencyHandler returns a middleware handler that honours the Idempotency-Key header of the POST and PATCH requests.
+// Keys are scoped by the principal. The first request with a key is processed and its response is stored,
+// the retries with the same payload get the stored response replayed with the Idempotent-Replayed header.
+// Reusing a key with a different payload or while its request is processed gets a 409 problem.
+// Responses with a 5xx status are not stored, so the request may be retried.
+// Neither are the responses marked "Cache-Control: no-store", e.g. those carrying tokens:
+// their retries are processed again.
+func IdempotencyHandler(store idempotency.Store, policy idempotency.Policy) iris.Handler {
+	return func(irisCtx iris.Context) {
+		key := irisCtx.GetHeader(headerIdempotencyKey)
+		method := irisCtx.Method()
+
+		if !policy.Enabled || key == "" || (method != iris.MethodPost && method != iris.MethodPatch) {
+			irisCtx.Next()
+
+			return
+		}
+
+		if len(key) > policy.MaxKeyLength {
+			api.HandleError(irisCtx, iris.StatusBadRequest, ErrIdempotencyKeyTooLong)
+
+			return
+		}
+
+		body, err := io.ReadAll(irisCtx.Request().Body)
+		if err != nil {
+			api.HandleError(irisCtx, api.RequestErrorStatus(err), fmt.Errorf("read request body: %w", err))
+
+			return
+		}
+
+		irisCtx.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := irisCtx.Request().Context()
+		lock := policy.NewLock(principalKey(irisCtx)+":"+key, requestFingerprint(irisCtx, body, policy.Secret), time.Now())
+
+		stored, err := store.Acquire(ctx, lock)
+
+		switch {
+		case errors.Is(err, idempotency.ErrKeyInUse):
+			irisCtx.Header(headerRetryAfter, "1")
+			api.HandleError(irisCtx, iris.StatusConflict, err)
+		case errors.Is(err, idempotency.ErrKeyMismatch):
+			api.HandleError(irisCtx, iris.StatusConflict, err)
+		case err != nil:
+			api.HandleError(irisCtx, iris.StatusInternalServerError, fmt.Errorf("acquire idempotency key: %w", err))
+		case stored != nil:
+			replayResponse(irisCtx, stored)
+		default:
+			processIdempotentRequest(irisCtx, store, lock)
+		}
+	}
+}
+
+// processIdempotentRequest executes the request holding the key and stores its response.
+// The key is released if the request fails or panics.
+func processIdempotentRequest(irisCtx iris.Context, store idempotency.Store, lock idempotency.Lock) {
+	// the key is stored even if the client has gone away meanwhile.
+	ctx := context.WithoutCancel(irisCtx.Request().Context())
+	completed := false
+
+	defer func() {
+		if completed {
+			return
+		}
+
+		if err := store.Release(ctx, lock); err != nil {
+			logger.FromContext(ctx).Error("release idempotency key", slog.Any("error", err))
+		}
+	}()
+
+	irisCtx.Record()
+	irisCtx.Next()
+
+	status := irisCtx.GetStatusCode()
+	if status >= iris.StatusInternalServerError || isNoStore(irisCtx.ResponseWriter().Header()) {
+		return
+	}
+
+	response := idempotency.Response{
+		StatusCode: status,
+		Header:     replayedHeader(irisCtx.ResponseWriter().Header()),
+		Body:       irisCtx.Recorder().Body(),
+	}
+
+	if err := store.Complete(ctx, lock, response); err != nil {
+		logger.FromContext(ctx).Error("store idempotent response", slog.Any("error", err))
+
+		return
+	}
+
+	completed = true
+}
+
+func replayResponse(irisCtx iris.Context, response *idempotency.Response) {
+	header := irisCtx.ResponseWriter().Header()
+	for name, values := range response.Header {
+		header[name] = values
+	}
+
+	irisCtx.Header(headerIdempotentReplayed, "true")
+	irisCtx.StatusCode(response.StatusCode)
+
+	if _, err := irisCtx.Write(response.Body); err != nil {
+		logger.FromContext(irisCtx.Request().Context()).Error("replay idempotent response", slog.Any("error", err))
+	}
+}
+
+func replayedHeader(header http.Header) http.Header {
+	replayed := http.Header{}
+
+	for name, values := range header {
+		if isNotReplayedHeader(name) {
+			continue
+		}
+
+		replayed[name] = append([]string(nil), values...)
+	}
+
+	return replayed
+}
+
+func isNotReplayedHeader(name string) bool {
+	for _, notReplayed := range notReplayedHeaders {
+		if strings.EqualFold(name, notReplayed) {
+			return true
+		}
+	}
+
+	return strings.HasPrefix(http.CanonicalHeaderKey(name), "Access-Control-")
+}
+
+// isNoStore reports whether the response forbids storing it by the "Cache-Control: no-store" directive.
+func isNoStore(header http.Header) bool {
+	for _, value := range header.Values(headerCacheControl) {
+		for _, directive := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(directive), directiveNoStore) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// requestFingerprint identifies the request payload by its method, path, media type and body.
+// It is an HMAC keyed by the secret, so the low entropy payloads, e.g. the credentials, can't be guessed from it.
+func requestFingerprint(irisCtx iris.Context, body, secret []byte) string {
+	hash := hmac.New(sha256.New, secret)
+
+	for _, part := range []string{irisCtx.Method(), irisCtx.Path(), irisCtx.GetHeader(headerContentType)} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
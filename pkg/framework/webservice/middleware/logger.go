@@ -1,28 +1,50 @@
 package middleware
 
 import (
+	"log/slog"
 	"time"
 
 	"github.com/kataras/iris/v12"
+
+	"solid-software.test-task/pkg/framework/ctxutils"
+	"solid-software.test-task/pkg/framework/logger"
 )
 
-// LoggerHandler returns a middleware handler that logs the duration of each request.
-// It logs the request method, request URI and the duration it took to process.
+// LoggerHandler returns a middleware handler that writes an access log record for each request.
+// The record carries the request method, URI, route template, response status and size,
+// latency, authenticated principal and request ID.
+// It is logged synchronously after the chain has finished, while the context is still valid.
 func LoggerHandler() iris.Handler {
-	return func(ctx iris.Context) {
-		startTime := time.Now().UTC()
+	return func(irisCtx iris.Context) {
+		startTime := time.Now()
+
+		irisCtx.Next()
 
-		ctx.Next()
+		latency := time.Since(startTime)
+		ctx := irisCtx.Request().Context()
 
-		duration := time.Since(startTime)
+		attrs := []any{
+			slog.String("method", irisCtx.Method()),
+			slog.String("uri", logger.RedactURI(irisCtx.Request().RequestURI)),
+			slog.String("route", routeTemplate(irisCtx)),
+			slog.Int("status", irisCtx.GetStatusCode()),
+			slog.Int("bytes", max(irisCtx.ResponseWriter().Written(), 0)),
+			slog.Duration("latency", latency),
+			slog.String("remote_ip", irisCtx.RemoteAddr()),
+		}
 
-		go func() {
-			ctx.Application().Logger().Infof(
-				"%s %s duration: %s",
-				ctx.Method(),
-				ctx.Request().RequestURI,
-				duration.String(),
-			)
-		}()
+		if username, ok := ctxutils.Username(ctx); ok {
+			attrs = append(attrs, slog.String("principal", username))
+		}
+
+		logger.FromContext(ctx).Info("access", attrs...)
 	}
 }
+
+func routeTemplate(irisCtx iris.Context) string {
+	if route := irisCtx.GetCurrentRoute(); route != nil {
+		return route.Path()
+	}
+
+	return ""
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/kataras/iris/v12"
+
+	"solid-software.test-task/pkg/framework/webservice/maintenance"
+	"solid-software.test-task/pkg/infra/api"
+)
+
+// MaintenanceHandler returns a middleware handler that rejects the mutating requests while the maintenance mode is on.
+// They get a 503 problem with the maintenance message and the Retry-After header; the safe methods keep working.
+func MaintenanceHandler() iris.Handler {
+	return func(irisCtx iris.Context) {
+		switch irisCtx.Method() {
+		case iris.MethodGet, iris.MethodHead, iris.MethodOptions:
+			irisCtx.Next()
+
+			return
+		}
+
+		status := maintenance.Current()
+		if !status.Enabled {
+			irisCtx.Next()
+
+			return
+		}
+
+		irisCtx.Header(headerRetryAfter, strconv.Itoa(int(math.Ceil(status.RetryAfter.Seconds()))))
+		api.HandleError(irisCtx, iris.StatusServiceUnavailable, fmt.Errorf("%w: %s", maintenance.ErrReadOnly, status.Message))
+	}
+}
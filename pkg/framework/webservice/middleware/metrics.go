@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/kataras/iris/v12"
+
+	"solid-software.test-task/pkg/framework/metrics"
+)
+
+const (
+	unmatchedRoute = "unmatched"
+)
+
+// MetricsHandler returns a middleware handler that records the HTTP metrics of each request.
+// Requests are labelled by the route template rather than the raw path, so the label cardinality stays bounded.
+func MetricsHandler() iris.Handler {
+	return func(irisCtx iris.Context) {
+		startTime := time.Now()
+
+		metrics.HTTPRequestStarted()
+		defer func() {
+			route := routeTemplate(irisCtx)
+			if route == "" {
+				route = unmatchedRoute
+			}
+
+			metrics.HTTPRequestFinished(route, irisCtx.Method(), irisCtx.GetStatusCode(), time.Since(startTime))
+		}()
+
+		irisCtx.Next()
+	}
+}
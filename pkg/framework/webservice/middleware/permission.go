@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kataras/iris/v12"
+
+	"solid-software.test-task/pkg/framework/ctxutils"
+	"solid-software.test-task/pkg/infra/api"
+)
+
+var (
+	// ErrPermissionDenied is returned when the authenticated client lacks a permission required by the route.
+	ErrPermissionDenied = errors.New("permission denied")
+)
+
+// PermissionHandler returns a middleware handler that requires the permissions from the authenticated client.
+// It must run after the AuthHandler. A client lacking any of them gets a 403 problem.
+// It returns nil if no permissions are required.
+func PermissionHandler(permissions []string) iris.Handler {
+	if len(permissions) == 0 {
+		return nil
+	}
+
+	return func(irisCtx iris.Context) {
+		granted := ctxutils.Permissions(irisCtx.Request().Context())
+
+		var missing []string
+
+		for _, permission := range permissions {
+			if !slices.Contains(granted, permission) {
+				missing = append(missing, permission)
+			}
+		}
+
+		if len(missing) > 0 {
+			api.HandleError(
+				irisCtx, iris.StatusForbidden, fmt.Errorf("%w: missing %s", ErrPermissionDenied, strings.Join(missing, ", ")),
+			)
+
+			return
+		}
+
+		irisCtx.Next()
+	}
+}
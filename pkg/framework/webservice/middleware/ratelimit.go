@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"errors"
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/kataras/iris/v12"
+
+	"solid-software.test-task/pkg/framework/logger"
+	"solid-software.test-task/pkg/framework/webservice/ratelimit"
+	"solid-software.test-task/pkg/infra/api"
+)
+
+const (
+	headerRateLimitLimit     = "RateLimit-Limit"
+	headerRateLimitRemaining = "RateLimit-Remaining"
+	headerRateLimitReset     = "RateLimit-Reset"
+	headerRetryAfter         = "Retry-After"
+)
+
+var (
+	// ErrRateLimitExceeded is returned when the client has exhausted its request quota.
+	ErrRateLimitExceeded = errors.New("rate limit exceeded")
+)
+
+// RateLimitHandler returns a middleware handler that limits the request rate using token buckets
+// with the limits of the current policy.
+// Requests are keyed by the authenticated username and fall back to the client IP.
+// Every response carries the RateLimit-* headers; rejected requests get a 429 problem with Retry-After.
+// If the store fails, the request is let through.
+func RateLimitHandler(store ratelimit.Store, currentPolicy func() ratelimit.Policy) iris.Handler {
+	return func(irisCtx iris.Context) {
+		policy := currentPolicy()
+		if !policy.Enabled {
+			irisCtx.Next()
+
+			return
+		}
+
+		limit, scope := policy.LimitFor(irisCtx.GetCurrentRoute().Path())
+		if limit.IsZero() {
+			irisCtx.Next()
+
+			return
+		}
+
+		result, err := store.Take(irisCtx.Request().Context(), scope+":"+principalKey(irisCtx), limit)
+		if err != nil {
+			logger.FromContext(irisCtx.Request().Context()).Error("rate limit store", slog.Any("error", err))
+			irisCtx.Next()
+
+			return
+		}
+
+		irisCtx.Header(headerRateLimitLimit, strconv.Itoa(result.Limit))
+		irisCtx.Header(headerRateLimitRemaining, strconv.Itoa(result.Remaining))
+		irisCtx.Header(headerRateLimitReset, durationToSeconds(result.Reset))
+
+		if !result.Allowed {
+			irisCtx.Header(headerRetryAfter, durationToSeconds(result.RetryAfter))
+			api.HandleError(irisCtx, iris.StatusTooManyRequests, ErrRateLimitExceeded)
+
+			return
+		}
+
+		irisCtx.Next()
+	}
+}
+
+func durationToSeconds(duration time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(duration.Seconds())))
+}
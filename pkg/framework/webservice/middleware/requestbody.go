@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kataras/iris/v12"
+	irisContext "github.com/kataras/iris/v12/context"
+
+	"solid-software.test-task/pkg/framework/webservice/requestbody"
+	"solid-software.test-task/pkg/infra/api"
+)
+
+const (
+	headerContentEncoding = "Content-Encoding"
+)
+
+var (
+	// decompressedEncodings are the content encodings of the request bodies decompressed transparently.
+	decompressedEncodings = []string{irisContext.GZIP, irisContext.DEFLATE, irisContext.BROTLI} //nolint:gochecknoglobals
+)
+
+// RequestBodyHandler returns a middleware handler that limits the size of the request body
+// and decompresses the bodies sent with the gzip, deflate or br Content-Encoding.
+// Bodies declaring a larger Content-Length get a 413 problem right away,
+// otherwise reading past the limit fails with requestbody.ErrTooLarge, which the handlers answer with 413.
+// The decompressed body is limited separately, so a small compressed body can't inflate without bounds.
+// Bodies with other encodings get a 415 problem.
+func RequestBodyHandler(policy requestbody.Policy) iris.Handler {
+	return func(irisCtx iris.Context) {
+		request := irisCtx.Request()
+		maxSize := policy.For(irisCtx.GetCurrentRoute().Path())
+
+		if maxSize > 0 && request.ContentLength > maxSize {
+			api.HandleError(
+				irisCtx, iris.StatusRequestEntityTooLarge,
+				fmt.Errorf("%w: the limit is %d bytes", requestbody.ErrTooLarge, maxSize),
+			)
+
+			return
+		}
+
+		request.Body = requestbody.LimitReader(request.Body, maxSize)
+
+		encoding := strings.ToLower(strings.TrimSpace(request.Header.Get(headerContentEncoding)))
+		if encoding == "" || encoding == irisContext.IDENTITY {
+			irisCtx.Next()
+
+			return
+		}
+
+		if !isDecompressedEncoding(encoding) {
+			api.HandleError(
+				irisCtx, iris.StatusUnsupportedMediaType, fmt.Errorf("%w: %q", requestbody.ErrUnsupportedEncoding, encoding),
+			)
+
+			return
+		}
+
+		reader, err := irisContext.NewCompressReader(request.Body, encoding)
+		if err != nil {
+			api.HandleError(irisCtx, iris.StatusBadRequest, fmt.Errorf("decompress request body: %w", err))
+
+			return
+		}
+
+		request.Body = requestbody.LimitReader(reader, policy.MaxDecompressed)
+		request.ContentLength = -1
+		request.Header.Del(headerContentEncoding)
+		request.Header.Del("Content-Length")
+
+		irisCtx.Next()
+	}
+}
+
+func isDecompressedEncoding(encoding string) bool {
+	for _, decompressed := range decompressedEncodings {
+		if encoding == decompressed {
+			return true
+		}
+	}
+
+	return false
+}
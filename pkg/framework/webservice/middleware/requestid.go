@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"regexp"
+
+	"github.com/google/uuid"
+	"github.com/kataras/iris/v12"
+
+	"solid-software.test-task/pkg/framework/ctxutils"
+)
+
+const (
+	// HeaderRequestID is the header carrying the request ID.
+	HeaderRequestID = "X-Request-ID"
+)
+
+var (
+	// _requestIDPattern limits the accepted request IDs, so they are safe to log and echo back.
+	_requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`) //nolint:gochecknoglobals
+)
+
+// RequestIDHandler returns a middleware handler that assigns an ID to every request.
+// The ID is taken from the X-Request-ID request header or generated if the header is missing or malformed.
+// It is stored in the request context under ctxutils.RequestIDContextKey and echoed in the response header.
+func RequestIDHandler() iris.Handler {
+	return func(irisCtx iris.Context) {
+		requestID := irisCtx.GetHeader(HeaderRequestID)
+		if !_requestIDPattern.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		setRequestContextValue(irisCtx, ctxutils.RequestIDContextKey, requestID)
+		irisCtx.Header(HeaderRequestID, requestID)
+
+		irisCtx.Next()
+	}
+}
@@ -2,11 +2,16 @@ package middleware
 
 import (
 	"fmt"
+	"log/slog"
 	"runtime"
 	"strings"
 
 	"github.com/google/uuid"
 	"github.com/kataras/iris/v12"
+
+	"solid-software.test-task/pkg/framework/ctxutils"
+	"solid-software.test-task/pkg/framework/logger"
+	"solid-software.test-task/pkg/infra/api"
 )
 
 // RecoveryHandler is a middleware that recovers from panics anywhere in the chain.
@@ -27,28 +32,29 @@ func handlePanic(ctx iris.Context) {
 		return
 	}
 
-	stacktrace := getStacktrace()
-	err := fmt.Errorf("panic: %v\n\n%s", panicVal, stacktrace)
+	requestID, ok := ctxutils.RequestID(ctx.Request().Context())
+	if !ok {
+		requestID = uuid.NewString()
+	}
 
-	logger := ctx.Application().Logger()
-	logger.Error(err)
+	log := logger.Default().With(logger.RequestIDKey, requestID)
+	log.Error("panic recovered", slog.String("panic", fmt.Sprint(panicVal)), slog.String("stacktrace", getStacktrace()))
 
-	correlationID := uuid.NewString()
-	problem := createInternalServerErrorProblem(correlationID)
+	problem := createInternalServerErrorProblem(requestID)
 
-	err = ctx.StopWithProblem(iris.StatusInternalServerError, problem)
+	err := ctx.StopWithProblem(iris.StatusInternalServerError, problem)
 	if err != nil {
-		logger.Error(err)
+		log.Error("stop with problem", slog.Any("error", err))
 
 		return
 	}
 }
 
-func createInternalServerErrorProblem(correlationID string) iris.Problem {
+func createInternalServerErrorProblem(requestID string) iris.Problem {
 	problem := iris.NewProblem()
 	problem.Title("Internal Server Error").
-		TempKey("correlationID", correlationID).
-		Detail("An unexpected error occurred. Please try again later or contact support and tell correlation id.").
+		Key(api.ProblemRequestIDKey, requestID).
+		Detail("An unexpected error occurred. Please try again later or contact support and tell the request id.").
 		Validate()
 
 	return problem
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kataras/iris/v12"
+	irisContext "github.com/kataras/iris/v12/context"
+
+	"solid-software.test-task/pkg/framework/webservice/timeout"
+	"solid-software.test-task/pkg/infra/api"
+)
+
+var (
+	// ErrRequestTimeout is returned when the request has not been handled within its timeout.
+	ErrRequestTimeout = errors.New("request timeout exceeded")
+)
+
+// TimeoutHandler returns a middleware handler that sets the deadline of the route timeout on the request context.
+// The handlers and the database queries using the context are canceled when the deadline is exceeded.
+// A request which exceeded its deadline without answering gets a 504 problem.
+func TimeoutHandler(policy timeout.Policy) iris.Handler {
+	return func(irisCtx iris.Context) {
+		requestTimeout := policy.For(irisCtx.GetCurrentRoute().Path())
+		if requestTimeout <= 0 {
+			irisCtx.Next()
+
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(irisCtx.Request().Context(), requestTimeout)
+		defer cancel()
+
+		irisCtx.ResetRequest(irisCtx.Request().WithContext(ctx))
+		irisCtx.Next()
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && !isAnswered(irisCtx) {
+			api.HandleError(irisCtx, iris.StatusGatewayTimeout, ErrRequestTimeout)
+		}
+	}
+}
+
+// isAnswered reports whether the handlers have answered the request.
+// A recorded response, e.g. of the idempotency middleware, is not written until the request ends,
+// so it is answered if it has a body or a status other than the default one.
+func isAnswered(irisCtx iris.Context) bool {
+	if recorder, ok := irisCtx.IsRecording(); ok {
+		return len(recorder.Body()) > 0 || recorder.StatusCode() != iris.StatusOK
+	}
+
+	return irisCtx.ResponseWriter().Written() != irisContext.NoWritten
+}
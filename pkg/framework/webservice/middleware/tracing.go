@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"github.com/kataras/iris/v12"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"solid-software.test-task/pkg/framework/ctxutils"
+	"solid-software.test-task/pkg/framework/tracing"
+)
+
+// TracingHandler returns a middleware handler that starts a server span for each request.
+// The incoming W3C traceparent header is honored, so the span continues the caller's trace.
+// The span is stored in the request context, so the handlers, services and stores create child spans of it.
+func TracingHandler() iris.Handler {
+	return func(irisCtx iris.Context) {
+		request := irisCtx.Request()
+		ctx := otel.GetTextMapPropagator().Extract(request.Context(), propagation.HeaderCarrier(request.Header))
+
+		ctx, span := tracing.StartSpan(
+			ctx,
+			request.Method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(semconv.HTTPMethod(request.Method), semconv.HTTPTarget(request.URL.Path)),
+		)
+		defer span.End()
+
+		if requestID, ok := ctxutils.RequestID(ctx); ok {
+			span.SetAttributes(attribute.String("request.id", requestID))
+		}
+
+		irisCtx.ResetRequest(request.WithContext(ctx))
+
+		irisCtx.Next()
+
+		status := irisCtx.GetStatusCode()
+		span.SetAttributes(semconv.HTTPStatusCode(status))
+
+		if route := routeTemplate(irisCtx); route != "" {
+			span.SetName(request.Method + " " + route)
+			span.SetAttributes(semconv.HTTPRoute(route))
+		}
+
+		if status >= iris.StatusInternalServerError {
+			span.SetStatus(codes.Error, iris.StatusText(status))
+		}
+	}
+}
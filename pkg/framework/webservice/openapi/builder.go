@@ -0,0 +1,215 @@
+package openapi
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"solid-software.test-task/pkg/framework/webservice/route"
+)
+
+const (
+	// Version is the OpenAPI specification version of the generated documents.
+	Version = "3.1.0"
+
+	// BearerAuthScheme is the name of the security scheme of the protected endpoints.
+	BearerAuthScheme = "bearerAuth"
+	// ProblemSchema is the name of the schema of the RFC 9457 problem details.
+	ProblemSchema = "Problem"
+
+	jsonMediaType    = "application/json"
+	problemMediaType = "application/problem+json"
+)
+
+var (
+	// pathParameterPattern matches the iris path parameters, e.g. "{id:uint}" or "{name}".
+	pathParameterPattern = regexp.MustCompile(`\{([A-Za-z0-9_]+)(?::([A-Za-z0-9_]+))?[^}]*}`)
+)
+
+type (
+	// Builder collects the registered endpoints into an OpenAPI document.
+	Builder struct {
+		document *Document
+	}
+)
+
+// NewBuilder returns a new builder of the document with the given title and API version.
+func NewBuilder(title, version string) *Builder {
+	builder := &Builder{
+		document: &Document{
+			OpenAPI: Version,
+			Info:    Info{Title: title, Version: version},
+			Paths:   map[string]*PathItem{},
+			Components: Components{
+				Schemas: map[string]*Schema{},
+				SecuritySchemes: map[string]*SecurityScheme{
+					BearerAuthScheme: {
+						Type:         "http",
+						Scheme:       "bearer",
+						BearerFormat: "JWT",
+					},
+				},
+			},
+		},
+	}
+	builder.document.Components.Schemas[ProblemSchema] = problemSchema()
+
+	return builder
+}
+
+// AddRoute adds the endpoint registered with the given method and iris path template.
+// The description is optional; endpoints without one are documented from their path only.
+func (b *Builder) AddRoute(method, path string, protected, deprecated bool, description *route.Operation) {
+	documentPath, parameters := b.pathParameters(path)
+
+	operation := &Operation{
+		OperationID: operationID(method, documentPath),
+		Parameters:  parameters,
+		Responses:   map[string]*Response{},
+		Deprecated:  deprecated,
+	}
+
+	status := http.StatusOK
+	responseContentType := jsonMediaType
+
+	if description != nil {
+		operation.Summary = description.Summary
+		operation.Description = description.Description
+		operation.Tags = description.Tags
+
+		for _, p := range description.Parameters {
+			operation.Parameters = append(operation.Parameters, Parameter{
+				Name:        p.Name,
+				In:          p.In,
+				Description: p.Description,
+				Required:    p.Required,
+				Schema:      &Schema{Type: parameterType(p.Type)},
+			})
+		}
+
+		if description.Request != nil {
+			operation.RequestBody = &RequestBody{
+				Required: true,
+				Content:  map[string]MediaType{jsonMediaType: {Schema: b.schemaOf(description.Request)}},
+			}
+		}
+
+		if description.Status != 0 {
+			status = description.Status
+		}
+
+		if description.ResponseContentType != "" {
+			responseContentType = description.ResponseContentType
+		}
+	}
+
+	response := &Response{Description: http.StatusText(status)}
+	if description != nil && description.Response != nil {
+		response.Content = map[string]MediaType{responseContentType: {Schema: b.schemaOf(description.Response)}}
+	}
+
+	operation.Responses[strconv.Itoa(status)] = response
+	operation.Responses["default"] = &Response{
+		Description: "Problem details of the failed request",
+		Content: map[string]MediaType{
+			problemMediaType: {Schema: &Schema{Ref: componentSchemasRef + ProblemSchema}},
+		},
+	}
+
+	if protected {
+		operation.Security = []SecurityRequirement{{BearerAuthScheme: {}}}
+		operation.Responses[strconv.Itoa(http.StatusUnauthorized)] = &Response{
+			Description: http.StatusText(http.StatusUnauthorized),
+		}
+	}
+
+	item, ok := b.document.Paths[documentPath]
+	if !ok {
+		item = &PathItem{}
+		b.document.Paths[documentPath] = item
+	}
+
+	(*item)[strings.ToLower(method)] = operation
+}
+
+// Document returns the collected document.
+func (b *Builder) Document() *Document {
+	return b.document
+}
+
+// pathParameters converts the iris path template to the OpenAPI one and documents its parameters.
+func (b *Builder) pathParameters(path string) (string, []Parameter) {
+	var parameters []Parameter
+
+	documentPath := pathParameterPattern.ReplaceAllStringFunc(path, func(match string) string {
+		groups := pathParameterPattern.FindStringSubmatch(match)
+		parameters = append(parameters, Parameter{
+			Name:     groups[1],
+			In:       "path",
+			Required: true,
+			Schema:   macroSchema(groups[2]),
+		})
+
+		return "{" + groups[1] + "}"
+	})
+
+	return documentPath, parameters
+}
+
+// macroSchema returns the schema of the iris path parameter macro.
+func macroSchema(macro string) *Schema {
+	minimum := 0.0
+
+	switch macro {
+	case "int", "int8", "int16", "int32", "int64":
+		return &Schema{Type: "integer"}
+	case "uint", "uint8", "uint16", "uint32", "uint64":
+		return &Schema{Type: "integer", Minimum: &minimum}
+	case "bool":
+		return &Schema{Type: "boolean"}
+	default:
+		return &Schema{Type: "string"}
+	}
+}
+
+func parameterType(parameterType string) string {
+	if parameterType == "" {
+		return "string"
+	}
+
+	return parameterType
+}
+
+// operationID builds a stable identifier of the operation, e.g. "getApiV1UserById" for GET /api/v1/user/{id}.
+func operationID(method, path string) string {
+	var id strings.Builder
+
+	id.WriteString(strings.ToLower(method))
+
+	for _, segment := range strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == '-' || r == '_' }) {
+		if strings.HasPrefix(segment, "{") {
+			id.WriteString("By")
+			segment = strings.Trim(segment, "{}")
+		}
+
+		id.WriteString(strings.ToUpper(segment[:1]) + segment[1:])
+	}
+
+	return id.String()
+}
+
+func problemSchema() *Schema {
+	return &Schema{
+		Type:        "object",
+		Description: "RFC 9457 problem details",
+		Properties: map[string]*Schema{
+			"type":      {Type: "string", Format: "uri-reference"},
+			"title":     {Type: "string"},
+			"status":    {Type: "integer"},
+			"detail":    {Type: "string"},
+			"instance":  {Type: "string", Format: "uri-reference"},
+			"requestId": {Type: "string"},
+		},
+	}
+}
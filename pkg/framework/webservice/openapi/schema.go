@@ -0,0 +1,208 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"solid-software.test-task/pkg/framework/validation"
+)
+
+const (
+	componentSchemasRef = "#/components/schemas/"
+)
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+)
+
+// schemaOf returns the schema of the given value type.
+// Named structs are registered in the component schemas and referenced.
+func (b *Builder) schemaOf(value any) *Schema {
+	return b.schemaOfType(reflect.TypeOf(value))
+}
+
+func (b *Builder) schemaOfType(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: "integer", Format: integerFormat(t)}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		minimum := 0.0
+
+		return &Schema{Type: "integer", Format: integerFormat(t), Minimum: &minimum}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+
+		return &Schema{Type: "array", Items: b.schemaOfType(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: b.schemaOfType(t.Elem())}
+	case reflect.Struct:
+		return b.structSchema(t)
+	default:
+		return &Schema{}
+	}
+}
+
+func (b *Builder) structSchema(t reflect.Type) *Schema {
+	name := schemaName(t)
+	if name == "" {
+		return b.objectSchema(t)
+	}
+
+	if _, ok := b.document.Components.Schemas[name]; !ok {
+		// the placeholder stops the recursion on self referencing types.
+		b.document.Components.Schemas[name] = &Schema{}
+		*b.document.Components.Schemas[name] = *b.objectSchema(t)
+	}
+
+	return &Schema{Ref: componentSchemasRef + name}
+}
+
+func (b *Builder) objectSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	rules := validation.RulesOf(t)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, omitEmpty, skip := jsonName(field)
+		if skip {
+			continue
+		}
+
+		if field.Anonymous && name == "" {
+			embedded := b.schemaOfType(field.Type)
+			if embedded.Ref != "" {
+				embedded = b.document.Components.Schemas[strings.TrimPrefix(embedded.Ref, componentSchemasRef)]
+			}
+
+			for propertyName, property := range embedded.Properties {
+				schema.Properties[propertyName] = property
+			}
+
+			schema.Required = append(schema.Required, embedded.Required...)
+
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		property := b.schemaOfType(field.Type)
+		required := !omitEmpty && field.Type.Kind() != reflect.Pointer
+
+		for _, rule := range rules[name] {
+			required = applyRule(property, rule) || required
+		}
+
+		schema.Properties[name] = property
+		if required {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+
+	return schema
+}
+
+// applyRule documents the validation rule in the property schema. It reports whether the rule makes the property required.
+func applyRule(property *Schema, rule validation.Rule) bool {
+	if property.Ref != "" {
+		return rule.Name == validation.RuleRequired
+	}
+
+	switch rule.Name {
+	case validation.RuleRequired:
+		return true
+	case validation.RuleMin:
+		if isNumber(property) {
+			minimum := float64(rule.Length)
+			property.Minimum = &minimum
+		} else {
+			property.MinLength = &rule.Length
+		}
+	case validation.RuleMax:
+		if isNumber(property) {
+			maximum := float64(rule.Length)
+			property.Maximum = &maximum
+		} else {
+			property.MaxLength = &rule.Length
+		}
+	case validation.RuleE164, validation.RulePattern:
+		property.Pattern = rule.Pattern.String()
+	}
+
+	return false
+}
+
+func isNumber(property *Schema) bool {
+	return property.Type == "integer" || property.Type == "number"
+}
+
+// jsonName returns the JSON member name of the field as defined by encoding/json.
+func jsonName(field reflect.StructField) (name string, omitEmpty, skip bool) {
+	tag, ok := field.Tag.Lookup("json")
+	if !ok {
+		return "", false, false
+	}
+
+	if tag == "-" {
+		return "", false, true
+	}
+
+	name, options, _ := strings.Cut(tag, ",")
+
+	return name, strings.Contains(options, "omitempty"), false
+}
+
+// schemaName returns the component name of the type, e.g. "user.Entity" becomes "UserEntity".
+func schemaName(t reflect.Type) string {
+	if t.Name() == "" {
+		return ""
+	}
+
+	pkg := t.PkgPath()
+	if i := strings.LastIndex(pkg, "/"); i >= 0 {
+		pkg = pkg[i+1:]
+	}
+
+	name := t.Name()
+	if i := strings.Index(name, "["); i >= 0 {
+		name = name[:i]
+	}
+
+	if pkg == "" || strings.EqualFold(pkg, name) {
+		return name
+	}
+
+	return strings.ToUpper(pkg[:1]) + pkg[1:] + name
+}
+
+func integerFormat(t reflect.Type) string {
+	if t.Bits() == 64 { //nolint:gomnd
+		return "int64"
+	}
+
+	return "int32"
+}
@@ -0,0 +1,110 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+type (
+	// MemoryStore is an in-process Store. Buckets are not shared between replicas.
+	MemoryStore struct {
+		mu      sync.Mutex
+		buckets map[string]*bucket
+		now     func() time.Time
+	}
+
+	bucket struct {
+		tokens   float64
+		updated  time.Time
+		capacity float64
+		rate     float64
+	}
+)
+
+const (
+	sweepInterval = time.Minute
+)
+
+// NewMemoryStore creates a new in-process Store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: map[string]*bucket{},
+		now:     time.Now,
+	}
+}
+
+// Take takes a token from the bucket identified by the key.
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	ratePerSecond := limit.RequestsPerMinute / float64(time.Minute/time.Second)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updated: now}
+		s.buckets[key] = b
+	}
+
+	b.capacity = float64(limit.Burst)
+	b.rate = ratePerSecond
+	b.refill(now)
+
+	result := Result{Limit: limit.Burst}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsToDuration((1 - b.tokens) / ratePerSecond)
+	}
+
+	result.Remaining = int(math.Floor(b.tokens))
+	result.Reset = secondsToDuration((b.capacity - b.tokens) / ratePerSecond)
+
+	return result, nil
+}
+
+// Sweep periodically removes the buckets which are full again and therefore carry no state.
+// It is a lifecycle worker and blocks until the context is canceled.
+func (s *MemoryStore) Sweep(ctx context.Context) {
+	ticker := time.NewTicker(sweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.sweep()
+		}
+	}
+}
+
+func (s *MemoryStore) sweep() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+
+	for key, b := range s.buckets {
+		b.refill(now)
+
+		if b.tokens >= b.capacity {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+func (b *bucket) refill(now time.Time) {
+	elapsed := now.Sub(b.updated).Seconds()
+	b.tokens = math.Min(b.capacity, b.tokens+elapsed*b.rate)
+	b.updated = now
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(math.Ceil(seconds * float64(time.Second)))
+}
@@ -0,0 +1,108 @@
+package ratelimit
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+
+	"solid-software.test-task/pkg/framework/config"
+)
+
+type (
+	// Limit describes a token bucket: it holds up to Burst tokens and is refilled with RequestsPerMinute tokens per minute.
+	Limit struct {
+		RequestsPerMinute float64
+		Burst             int
+	}
+
+	// Result is the outcome of taking a token from a bucket.
+	Result struct {
+		// Allowed reports whether a token was taken.
+		Allowed bool
+		// Limit is the bucket capacity.
+		Limit int
+		// Remaining is the number of tokens left in the bucket.
+		Remaining int
+		// Reset is the time until the bucket is full again.
+		Reset time.Duration
+		// RetryAfter is the time until the next token is available. It is zero when the request is allowed.
+		RetryAfter time.Duration
+	}
+
+	// Store keeps the token buckets.
+	// Implementations must be safe for concurrent use; a shared implementation
+	// (e.g. backed by Redis) allows applying the limits across replicas.
+	Store interface {
+		// Take takes a token from the bucket identified by the key.
+		Take(ctx context.Context, key string, limit Limit) (Result, error)
+	}
+
+	// Policy holds the default limit and the limits of specific routes.
+	Policy struct {
+		Enabled bool
+		Default Limit
+		// Routes maps route path templates (e.g. "/api/v1/token/generate") to their limits.
+		Routes map[string]Limit
+		// defaultScope is the bucket scope of the default limit; it is set when a route overrides the default.
+		defaultScope string
+	}
+)
+
+const (
+	defaultScope = "default"
+)
+
+// IsZero reports whether the limit is not set.
+func (l Limit) IsZero() bool {
+	return l.RequestsPerMinute <= 0 || l.Burst <= 0
+}
+
+// NewPolicy reads the rate limit policy from the "webService.rateLimit" config section.
+func NewPolicy(cfg config.Config) Policy {
+	policy := Policy{
+		Enabled: cfg.GetBool("webService.rateLimit.enabled"),
+		Default: Limit{
+			RequestsPerMinute: cfg.GetFloat64("webService.rateLimit.requestsPerMinute"),
+			Burst:             cfg.GetInt("webService.rateLimit.burst"),
+		},
+		Routes: map[string]Limit{},
+	}
+
+	for path, value := range cfg.GetStringMap("webService.rateLimit.routes") {
+		routeLimit := cast.ToStringMap(value)
+		policy.Routes[strings.ToLower(path)] = Limit{
+			RequestsPerMinute: cast.ToFloat64(routeLimit["requestsperminute"]),
+			Burst:             cast.ToInt(routeLimit["burst"]),
+		}
+	}
+
+	return policy
+}
+
+// WithDefault returns a copy of the policy with the default limit replaced, if the limit is set.
+// The replaced limit gets its own buckets scoped by the route name, so they aren't shared
+// with the routes of the global default or of other overrides.
+func (p Policy) WithDefault(routeName string, limit *Limit) Policy {
+	if limit != nil && !limit.IsZero() {
+		p.Default = *limit
+		p.defaultScope = "route:" + routeName
+	}
+
+	return p
+}
+
+// LimitFor returns the limit of the route and the scope of its bucket.
+// Routes without a specific limit share the default bucket, unless the default is overridden for the route.
+func (p Policy) LimitFor(routePath string) (Limit, string) {
+	if limit, ok := p.Routes[strings.ToLower(routePath)]; ok && !limit.IsZero() {
+		return limit, routePath
+	}
+
+	if p.defaultScope != "" {
+		return p.Default, p.defaultScope
+	}
+
+	return p.Default, defaultScope
+}
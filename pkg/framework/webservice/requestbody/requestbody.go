@@ -0,0 +1,107 @@
+package requestbody
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"solid-software.test-task/pkg/framework/config"
+)
+
+type (
+	// Policy holds the request body size limits.
+	Policy struct {
+		// Default is the size limit in bytes of the request bodies as received, zero means unlimited.
+		Default int64
+		// MaxDecompressed is the size limit in bytes of the decompressed request bodies, zero means unlimited.
+		// It protects from decompression bombs.
+		MaxDecompressed int64
+		// Routes maps route path templates (e.g. "/api/v1/user") to their size limits.
+		Routes map[string]int64
+	}
+
+	limitedReader struct {
+		reader    io.ReadCloser
+		remaining int64
+		err       error
+	}
+)
+
+var (
+	// ErrTooLarge is returned when the request body exceeds its size limit.
+	ErrTooLarge = errors.New("request body too large")
+	// ErrUnsupportedEncoding is returned when the request body is compressed with an unsupported encoding.
+	ErrUnsupportedEncoding = errors.New("unsupported content encoding")
+)
+
+// NewPolicy reads the request body policy from the "webService.requestBody" config section.
+func NewPolicy(cfg config.Config) Policy {
+	policy := Policy{
+		Default:         int64(cfg.GetSizeInBytes("webService.requestBody.maxSize")),
+		MaxDecompressed: int64(cfg.GetSizeInBytes("webService.requestBody.maxDecompressedSize")),
+		Routes:          map[string]int64{},
+	}
+
+	for path, value := range cfg.GetStringMap("webService.requestBody.routes") {
+		policy.Routes[strings.ToLower(path)] = int64(config.SizeInBytes(value))
+	}
+
+	return policy
+}
+
+// WithDefault returns a copy of the policy with the default limit replaced, if the limit is set.
+func (p Policy) WithDefault(maxSize int64) Policy {
+	if maxSize > 0 {
+		p.Default = maxSize
+	}
+
+	return p
+}
+
+// For returns the size limit of the route.
+func (p Policy) For(routePath string) int64 {
+	if maxSize, ok := p.Routes[strings.ToLower(routePath)]; ok && maxSize > 0 {
+		return maxSize
+	}
+
+	return p.Default
+}
+
+// LimitReader returns the reader failing with ErrTooLarge once more than maxSize bytes are read.
+// The reader is returned as is if maxSize is not positive.
+func LimitReader(reader io.ReadCloser, maxSize int64) io.ReadCloser {
+	if maxSize <= 0 {
+		return reader
+	}
+
+	return &limitedReader{
+		reader:    reader,
+		remaining: maxSize,
+		err:       fmt.Errorf("%w: the limit is %d bytes", ErrTooLarge, maxSize),
+	}
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	if r.remaining < 0 {
+		return 0, r.err
+	}
+
+	// one byte over the limit is read to tell a body of exactly the limit size from a larger one.
+	if int64(len(p)) > r.remaining+1 {
+		p = p[:r.remaining+1]
+	}
+
+	n, err := r.reader.Read(p)
+	r.remaining -= int64(n)
+
+	if r.remaining < 0 {
+		return n + int(r.remaining), r.err
+	}
+
+	return n, err //nolint:wrapcheck
+}
+
+func (r *limitedReader) Close() error {
+	return r.reader.Close() //nolint:wrapcheck
+}
@@ -0,0 +1,91 @@
+package route
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"solid-software.test-task/pkg/framework/webservice/ratelimit"
+)
+
+type (
+	// Endpoint describes a mounted endpoint with the effective settings of its route.
+	Endpoint struct {
+		// Route is the name of the route declaring the endpoint.
+		Route string
+		// Method is the HTTP method of the endpoint.
+		Method string
+		// Path is the full path template of the endpoint, e.g. "/api/v1/user/{id:uint}".
+		Path string
+		// Version is the API version serving the endpoint.
+		Version string
+		// Deprecated reports whether the API version is deprecated.
+		Deprecated bool
+		// Protected reports whether the endpoint requires authentication.
+		Protected bool
+		// Permissions are required from the authenticated client.
+		Permissions []string
+		// Middleware lists the names of the handlers executed before the endpoint handler.
+		Middleware []string
+		// Handler is the name of the endpoint handler.
+		Handler string
+		// Timeout is the request timeout, zero if not limited.
+		Timeout time.Duration
+		// RateLimit is the rate limit, nil if not limited.
+		RateLimit *ratelimit.Limit
+		// CacheControl is the Cache-Control value of the GET responses.
+		CacheControl string
+		// MaxBodySize is the request body size limit in bytes, zero if not limited.
+		MaxBodySize int64
+	}
+
+	// Registry collects the mounted endpoints. It is safe for concurrent use.
+	Registry struct {
+		mu        sync.RWMutex
+		endpoints []Endpoint
+	}
+)
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Add registers the endpoints.
+func (r *Registry) Add(endpoints ...Endpoint) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.endpoints = append(r.endpoints, endpoints...)
+}
+
+// Endpoints returns the registered endpoints sorted by their path and method.
+func (r *Registry) Endpoints() []Endpoint {
+	r.mu.RLock()
+	endpoints := append([]Endpoint(nil), r.endpoints...)
+	r.mu.RUnlock()
+
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		if endpoints[i].Path != endpoints[j].Path {
+			return endpoints[i].Path < endpoints[j].Path
+		}
+
+		return endpoints[i].Method < endpoints[j].Method
+	})
+
+	return endpoints
+}
+
+// HasRoute reports whether endpoints of the named route are registered.
+func (r *Registry) HasRoute(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, endpoint := range r.endpoints {
+		if endpoint.Route == name {
+			return true
+		}
+	}
+
+	return false
+}
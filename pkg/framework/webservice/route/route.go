@@ -1,7 +1,21 @@
 package route
 
 import (
+	"path"
+	"reflect"
+	"slices"
+	"time"
+
+	irisContext "github.com/kataras/iris/v12/context"
 	"github.com/kataras/iris/v12/core/router"
+
+	"solid-software.test-task/pkg/framework/webservice/cors"
+	"solid-software.test-task/pkg/framework/webservice/ratelimit"
+)
+
+const (
+	// DefaultVersion is the API version served by the routes which are not Versioned.
+	DefaultVersion = "v1"
 )
 
 type (
@@ -12,4 +26,186 @@ type (
 		// InitRoutes initializes endpoint or group of endpoints.
 		InitRoutes(p router.Party)
 	}
+
+	// Configurable is an optional interface of Route.
+	// Routes implementing it override the web service defaults for their endpoints.
+	Configurable interface {
+		Route
+		// Settings returns the route specific settings.
+		Settings() Settings
+	}
+
+	// Described is an optional interface of Route.
+	// Routes implementing it declare their name, required permissions, middleware and settings.
+	// It supersedes Configurable: the settings of a Described route are taken from its metadata.
+	Described interface {
+		Route
+		// Metadata returns the route metadata.
+		Metadata() Metadata
+	}
+
+	// Versioned is an optional interface of Route.
+	// Routes implementing it are mounted under each API version they serve instead of the default one.
+	Versioned interface {
+		Route
+		// Versions returns the API versions served by the route, e.g. "v1" and "v2".
+		Versions() []string
+	}
+
+	// Documented is an optional interface of Route.
+	// Routes implementing it describe their endpoints for the generated API documentation.
+	Documented interface {
+		Route
+		// Operations returns the descriptions of the route endpoints.
+		Operations() []Operation
+	}
+
+	// Operation describes an endpoint for the API documentation.
+	Operation struct {
+		// Method is the HTTP method of the endpoint.
+		Method string
+		// Path is the endpoint path relative to the API root in the iris template syntax, e.g. "/user/{id:uint}".
+		// Path parameters are documented from the template.
+		Path string
+		// Summary is a short description of the endpoint.
+		Summary string
+		// Description is a verbose description of the endpoint.
+		Description string
+		// Tags group the endpoints in the documentation.
+		Tags []string
+		// Parameters describes the query and header parameters.
+		Parameters []Parameter
+		// Request is a value of the request body type, e.g. user.Entity{}. Nil means no request body.
+		Request any
+		// Response is a value of the response body type, e.g. []user.Entity{}. Nil means no response body.
+		Response any
+		// ResponseContentType is the media type of the response body. Defaults to application/json.
+		ResponseContentType string
+		// Status is the status code of a successful response. Defaults to 200.
+		Status int
+	}
+
+	// Parameter describes a query or header parameter of an endpoint.
+	Parameter struct {
+		// Name is the name of the parameter.
+		Name string
+		// In is the location of the parameter: "query" or "header".
+		In string
+		// Description describes the parameter.
+		Description string
+		// Required reports whether the parameter is mandatory.
+		Required bool
+		// Type is the JSON schema type of the parameter. Defaults to "string".
+		Type string
+	}
+
+	// Metadata describes a route. Zero values keep the defaults.
+	Metadata struct {
+		// Name is the stable name of the route, e.g. "user". Defaults to the package name of the route.
+		Name string
+		// Description describes the route for the operators.
+		Description string
+		// Permissions are required from the authenticated client of a protected route, e.g. "users".
+		Permissions []string
+		// EndpointPermissions are required on top of Permissions by the endpoints of a protected route,
+		// keyed by the method and the path relative to the API version root,
+		// e.g. "PUT /user/{id:uint}/credentials".
+		EndpointPermissions map[string][]string
+		// Middleware is executed after the web service middleware, right before the route handlers.
+		Middleware []irisContext.Handler
+		// WritableInMaintenance keeps the mutating endpoints of the route working in the maintenance mode,
+		// e.g. the login, so the clients can still get the tokens for the reads.
+		WritableInMaintenance bool
+		// Settings overrides the web service defaults for the route endpoints.
+		Settings
+	}
+
+	// Settings holds the route specific overrides of the web service defaults.
+	// Zero values keep the defaults.
+	Settings struct {
+		// CORS overrides the global CORS policy.
+		CORS *cors.Policy
+		// RateLimit overrides the global rate limit. Limits configured for a specific path take precedence.
+		RateLimit *ratelimit.Limit
+		// CacheControl overrides the global Cache-Control value of the GET responses.
+		// Values configured for a specific path take precedence.
+		CacheControl string
+		// Timeout overrides the global request timeout. Timeouts configured for a specific path take precedence.
+		Timeout time.Duration
+		// MaxBodySize overrides the global request body size limit in bytes.
+		// Limits configured for a specific path take precedence.
+		MaxBodySize int64
+		// DisableIdempotency opts the route out of the Idempotency-Key handling,
+		// e.g. for the responses carrying secrets which must not be stored.
+		DisableIdempotency bool
+	}
 )
+
+// MetadataOf returns the metadata of the route.
+// Routes which are not Described get the default name and the settings of a Configurable route.
+func MetadataOf(r Route) Metadata {
+	var metadata Metadata
+
+	switch described := r.(type) {
+	case Described:
+		metadata = described.Metadata()
+	case Configurable:
+		metadata.Settings = described.Settings()
+	}
+
+	if metadata.Name == "" {
+		metadata.Name = defaultName(r)
+	}
+
+	return metadata
+}
+
+// PermissionsFor returns the permissions required by the endpoint of the route:
+// the ones of the route followed by the ones of the endpoint.
+func (m Metadata) PermissionsFor(method, relativePath string) []string {
+	endpointPermissions := m.EndpointPermissions[method+" "+relativePath]
+	if len(endpointPermissions) == 0 {
+		return m.Permissions
+	}
+
+	return append(slices.Clone(m.Permissions), endpointPermissions...)
+}
+
+// SettingsOf returns the settings of the route, or empty settings if the route is neither Described nor Configurable.
+func SettingsOf(r Route) Settings {
+	return MetadataOf(r).Settings
+}
+
+// OperationsOf returns the documented operations of the route, or nil if the route is not Documented.
+func OperationsOf(r Route) []Operation {
+	if documented, ok := r.(Documented); ok {
+		return documented.Operations()
+	}
+
+	return nil
+}
+
+// VersionsOf returns the API versions served by the route, or the default version if the route is not Versioned.
+func VersionsOf(r Route) []string {
+	if versioned, ok := r.(Versioned); ok {
+		if versions := versioned.Versions(); len(versions) > 0 {
+			return versions
+		}
+	}
+
+	return []string{DefaultVersion}
+}
+
+// defaultName names the route by the package declaring it, e.g. "user" for the user API.
+func defaultName(r Route) string {
+	routeType := reflect.TypeOf(r)
+	for routeType.Kind() == reflect.Pointer {
+		routeType = routeType.Elem()
+	}
+
+	if routeType.PkgPath() == "" {
+		return routeType.String()
+	}
+
+	return path.Base(routeType.PkgPath())
+}
@@ -0,0 +1,207 @@
+package webservice
+
+import (
+	"strings"
+
+	"github.com/kataras/iris/v12"
+	irisContext "github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/core/router"
+
+	"solid-software.test-task/pkg/framework/webservice/route"
+	"solid-software.test-task/pkg/framework/webservice/versioning"
+	"solid-software.test-task/pkg/infra/api"
+)
+
+const (
+	routesRouteName = "routes"
+	routesPath      = "/_routes"
+)
+
+type (
+	routesAPI struct {
+		service *webService
+	}
+
+	routesResponse struct {
+		Routes []endpointResponse `json:"routes"`
+	}
+
+	endpointResponse struct {
+		Route        string             `json:"route"`
+		Method       string             `json:"method"`
+		Path         string             `json:"path"`
+		Version      string             `json:"version"`
+		Deprecated   bool               `json:"deprecated,omitempty"`
+		Protected    bool               `json:"protected"`
+		Permissions  []string           `json:"permissions,omitempty"`
+		Middleware   []string           `json:"middleware"`
+		Handler      string             `json:"handler"`
+		Timeout      string             `json:"timeout,omitempty"`
+		RateLimit    *rateLimitResponse `json:"rateLimit,omitempty"`
+		CacheControl string             `json:"cacheControl,omitempty"`
+		MaxBodySize  int64              `json:"maxBodySize,omitempty"`
+	}
+
+	rateLimitResponse struct {
+		RequestsPerMinute float64 `json:"requestsPerMinute"`
+		Burst             int     `json:"burst"`
+	}
+)
+
+// newRoutesAPI returns the route listing the endpoints of the registry for the operators.
+func newRoutesAPI(service *webService) route.Route {
+	return &routesAPI{service: service}
+}
+
+// IsProtected indicates that the route listing is a protected route.
+func (*routesAPI) IsProtected() bool {
+	return true
+}
+
+// Metadata requires the "routes" permission.
+func (*routesAPI) Metadata() route.Metadata {
+	return route.Metadata{
+		Name:        routesRouteName,
+		Description: "Lists the mounted endpoints and how they are protected.",
+		Permissions: []string{routesRouteName},
+	}
+}
+
+// InitRoutes inits the route listing.
+func (a *routesAPI) InitRoutes(party router.Party) {
+	party.Get(routesPath, a.handleGetRoutes)
+}
+
+// Operations describes the route listing.
+func (*routesAPI) Operations() []route.Operation {
+	return []route.Operation{
+		{
+			Method:      iris.MethodGet,
+			Path:        routesPath,
+			Summary:     "List the mounted endpoints",
+			Description: "Lists the endpoints of every API version with their protection and effective settings.",
+			Tags:        []string{"routes"},
+			Response:    routesResponse{},
+		},
+	}
+}
+
+func (a *routesAPI) handleGetRoutes(irisCtx iris.Context) {
+	endpoints := a.service.routeRegistry.Endpoints()
+	response := routesResponse{Routes: make([]endpointResponse, 0, len(endpoints))}
+
+	for _, endpoint := range endpoints {
+		if resolve, ok := a.service.endpointResolvers[endpointKey(endpoint.Method, endpoint.Path)]; ok {
+			resolve(&endpoint)
+		}
+
+		response.Routes = append(response.Routes, toEndpointResponse(endpoint))
+	}
+
+	api.Respond(irisCtx, iris.StatusOK, response)
+}
+
+// withRoutesEndpoint adds the route listing to the routes, unless it is already mounted.
+func (w *webService) withRoutesEndpoint(routes []route.Route) []route.Route {
+	if w.routeRegistry.HasRoute(routesRouteName) {
+		return routes
+	}
+
+	return append(routes, newRoutesAPI(w))
+}
+
+// registerEndpoints adds the endpoints of the route mounted under the version to the registry.
+func (w *webService) registerEndpoints(
+	metadata route.Metadata, protected bool, version versioning.Version, registered []*router.Route,
+	policies routePolicies,
+) {
+	endpoints := make([]route.Endpoint, 0, len(registered))
+
+	for _, registeredRoute := range registered {
+		endpoint := route.Endpoint{
+			Route:        metadata.Name,
+			Method:       registeredRoute.Method,
+			Path:         registeredRoute.Tmpl().Src,
+			Version:      version.Name,
+			Deprecated:   version.Deprecated,
+			Protected:    protected,
+			Permissions:  metadata.PermissionsFor(registeredRoute.Method, relativePath(registeredRoute, version)),
+			Handler:      handlerName(registeredRoute.MainHandlerName),
+			Timeout:      policies.timeout.For(registeredRoute.Path),
+			CacheControl: policies.cacheControl.For(registeredRoute.Path),
+			MaxBodySize:  policies.requestBody.For(registeredRoute.Path),
+		}
+
+		routePath := registeredRoute.Path
+		w.endpointResolvers[endpointKey(endpoint.Method, endpoint.Path)] = func(endpoint *route.Endpoint) {
+			endpoint.RateLimit = nil
+
+			if rateLimitPolicy := policies.rateLimit(); rateLimitPolicy.Enabled {
+				if limit, _ := rateLimitPolicy.LimitFor(routePath); !limit.IsZero() {
+					endpoint.RateLimit = &limit
+				}
+			}
+		}
+
+		for _, handler := range registeredRoute.Handlers[:len(registeredRoute.Handlers)-1] {
+			endpoint.Middleware = append(endpoint.Middleware, handlerName(irisContext.HandlerName(handler)))
+		}
+
+		endpoints = append(endpoints, endpoint)
+	}
+
+	w.routeRegistry.Add(endpoints...)
+}
+
+func endpointKey(method, path string) string {
+	return method + " " + path
+}
+
+// relativePath returns the path template of the mounted route relative to the root of its version,
+// e.g. "/user/{id:uint}" for "/api/v1/user/{id:uint}".
+func relativePath(registeredRoute *router.Route, version versioning.Version) string {
+	return strings.TrimPrefix(registeredRoute.Tmpl().Src, version.Path)
+}
+
+// handlerName shortens the handler name to its package, e.g. "middleware.CORSHandler".
+func handlerName(name string) string {
+	name = strings.TrimSuffix(name[strings.LastIndex(name, "/")+1:], "-fm")
+
+	for {
+		trimmed, ok := strings.CutSuffix(name, ".func1")
+		if !ok {
+			return name
+		}
+
+		name = trimmed
+	}
+}
+
+func toEndpointResponse(endpoint route.Endpoint) endpointResponse {
+	response := endpointResponse{
+		Route:        endpoint.Route,
+		Method:       endpoint.Method,
+		Path:         endpoint.Path,
+		Version:      endpoint.Version,
+		Deprecated:   endpoint.Deprecated,
+		Protected:    endpoint.Protected,
+		Permissions:  endpoint.Permissions,
+		Middleware:   endpoint.Middleware,
+		Handler:      endpoint.Handler,
+		CacheControl: endpoint.CacheControl,
+		MaxBodySize:  endpoint.MaxBodySize,
+	}
+
+	if endpoint.Timeout > 0 {
+		response.Timeout = endpoint.Timeout.String()
+	}
+
+	if endpoint.RateLimit != nil {
+		response.RateLimit = &rateLimitResponse{
+			RequestsPerMinute: endpoint.RateLimit.RequestsPerMinute,
+			Burst:             endpoint.RateLimit.Burst,
+		}
+	}
+
+	return response
+}
@@ -0,0 +1,71 @@
+package webservice
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"time"
+)
+
+const (
+	staleSocketDialTimeout = time.Second
+)
+
+var (
+	// ErrSocketInUse is returned when the Unix socket path is served by another process.
+	ErrSocketInUse = errors.New("socket is in use")
+	// ErrNotSocket is returned when the Unix socket path is taken by a file which is not a socket.
+	ErrNotSocket = errors.New("path exists and is not a socket")
+)
+
+// listenUnix listens on the Unix socket path with the file mode.
+// A stale socket left by a crashed process is removed first; the socket is removed when the listener is closed.
+// The socket is created with the mode through the umask, so it is never reachable with a wider one.
+// The umask is process wide, so it is restored right after the socket is created.
+func listenUnix(path string, mode os.FileMode) (net.Listener, error) {
+	if err := removeStaleSocket(path); err != nil {
+		return nil, err
+	}
+
+	umask := syscall.Umask(int(^mode & os.ModePerm))
+	listener, err := net.Listen(networkUnix, path)
+	syscall.Umask(umask)
+
+	if err != nil {
+		return nil, fmt.Errorf("listen unix: %w", err)
+	}
+
+	listener.(*net.UnixListener).SetUnlinkOnClose(true)
+
+	return listener, nil
+}
+
+// removeStaleSocket removes the socket at the path unless a process still accepts connections on it.
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("stat socket: %w", err)
+	}
+
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%w: %s", ErrNotSocket, path)
+	}
+
+	if conn, err := net.DialTimeout(networkUnix, path, staleSocketDialTimeout); err == nil {
+		_ = conn.Close()
+
+		return fmt.Errorf("%w: %s", ErrSocketInUse, path)
+	}
+
+	if err = os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove stale socket: %w", err)
+	}
+
+	return nil
+}
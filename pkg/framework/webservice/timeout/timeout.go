@@ -0,0 +1,52 @@
+package timeout
+
+import (
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+
+	"solid-software.test-task/pkg/framework/config"
+)
+
+type (
+	// Policy holds the request timeouts.
+	Policy struct {
+		// Default is the timeout of the routes without a specific one. Zero disables the timeout.
+		Default time.Duration
+		// Routes maps route path templates (e.g. "/api/v1/users") to their timeouts.
+		Routes map[string]time.Duration
+	}
+)
+
+// NewPolicy reads the request timeout policy from the "webService.requestTimeout" config section.
+func NewPolicy(cfg config.Config) Policy {
+	policy := Policy{
+		Default: cfg.GetDuration("webService.requestTimeout.default"),
+		Routes:  map[string]time.Duration{},
+	}
+
+	for path, value := range cfg.GetStringMap("webService.requestTimeout.routes") {
+		policy.Routes[strings.ToLower(path)] = cast.ToDuration(value)
+	}
+
+	return policy
+}
+
+// WithDefault returns a copy of the policy with the default timeout replaced, if the timeout is set.
+func (p Policy) WithDefault(timeout time.Duration) Policy {
+	if timeout > 0 {
+		p.Default = timeout
+	}
+
+	return p
+}
+
+// For returns the timeout of the route.
+func (p Policy) For(routePath string) time.Duration {
+	if timeout, ok := p.Routes[strings.ToLower(routePath)]; ok {
+		return timeout
+	}
+
+	return p.Default
+}
@@ -0,0 +1,128 @@
+package tlsconfig
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+
+	"solid-software.test-task/pkg/framework/logger"
+)
+
+type (
+	// CertificateReloader keeps the server certificate loaded from the certificate and key files
+	// and reloads it when any of the files changes.
+	CertificateReloader struct {
+		certFile string
+		keyFile  string
+
+		mu   sync.RWMutex
+		cert *tls.Certificate
+	}
+)
+
+// NewCertificateReloader loads the certificate and key pair and returns a reloader serving it.
+func NewCertificateReloader(certFile, keyFile string) (*CertificateReloader, error) {
+	reloader := &CertificateReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+	}
+
+	if err := reloader.Reload(); err != nil {
+		return nil, err
+	}
+
+	return reloader, nil
+}
+
+// Reload reads the certificate and key pair from the files.
+// The previously loaded certificate is kept if the new pair cannot be loaded.
+func (r *CertificateReloader) Reload() error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("load certificate %q and key %q: %w", r.certFile, r.keyFile, err)
+	}
+
+	r.mu.Lock()
+	r.cert = &cert
+	r.mu.Unlock()
+
+	return nil
+}
+
+// GetCertificate returns the currently loaded certificate.
+// It is intended to be used as tls.Config.GetCertificate.
+func (r *CertificateReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.cert, nil
+}
+
+// Watch watches the directories of the certificate and key files and reloads the certificate on changes.
+// Directories are watched instead of the files, so atomic replacements
+// (e.g. Kubernetes secret volume updates) are noticed as well.
+// It blocks until the context is canceled.
+func (r *CertificateReloader) Watch(ctx context.Context) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		logger.Default().Error("tls certificate watcher", slog.Any("error", err))
+
+		return
+	}
+	defer watcher.Close()
+
+	for _, dir := range r.watchedDirs() {
+		if err = watcher.Add(dir); err != nil {
+			logger.Default().Error("tls certificate watcher", slog.String("dir", dir), slog.Any("error", err))
+
+			return
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+
+			if !r.isWatchedFile(event.Name) && !event.Has(fsnotify.Create) {
+				continue
+			}
+
+			if err = r.Reload(); err != nil {
+				logger.Default().Error("tls certificate reload", slog.Any("error", err))
+			} else {
+				logger.Default().Info("tls certificate reloaded", slog.String("file", event.Name))
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+
+			logger.Default().Error("tls certificate watcher", slog.Any("error", err))
+		}
+	}
+}
+
+func (r *CertificateReloader) watchedDirs() []string {
+	certDir, keyDir := filepath.Dir(r.certFile), filepath.Dir(r.keyFile)
+	if certDir == keyDir {
+		return []string{certDir}
+	}
+
+	return []string{certDir, keyDir}
+}
+
+func (r *CertificateReloader) isWatchedFile(name string) bool {
+	name = filepath.Clean(name)
+
+	return name == filepath.Clean(r.certFile) || name == filepath.Clean(r.keyFile)
+}
@@ -0,0 +1,133 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"solid-software.test-task/pkg/framework/config"
+)
+
+const (
+	clientAuthRequire  = "require"
+	clientAuthOptional = "optional"
+)
+
+var (
+	// ErrUnknownTLSVersion is returned when the configured minimum TLS version is not supported.
+	ErrUnknownTLSVersion = errors.New("unknown TLS version")
+	// ErrUnknownCipherSuite is returned when a configured cipher suite is unknown or insecure.
+	ErrUnknownCipherSuite = errors.New("unknown or insecure cipher suite")
+	// ErrUnknownClientAuth is returned when the configured client authentication mode is not supported.
+	ErrUnknownClientAuth = errors.New("unknown client authentication mode")
+	// ErrNoClientCA is returned when the client CA bundle contains no certificates.
+	ErrNoClientCA = errors.New("no certificates found in client CA bundle")
+)
+
+// Enabled reports whether TLS is enabled for the web service.
+func Enabled(cfg config.Config) bool {
+	return cfg.GetBool("webService.tls.enabled")
+}
+
+// New builds the server TLS configuration from the "webService.tls" config section.
+// The returned reloader serves the certificate and must be watched to pick up certificate file changes.
+func New(cfg config.Config) (*tls.Config, *CertificateReloader, error) {
+	reloader, err := NewCertificateReloader(
+		cfg.GetString("webService.tls.certFile"),
+		cfg.GetString("webService.tls.keyFile"),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	minVersion, err := parseVersion(cfg.GetString("webService.tls.minVersion"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cipherSuites, err := parseCipherSuites(cfg.GetStringSlice("webService.tls.cipherSuites"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:     minVersion,
+		CipherSuites:   cipherSuites,
+		GetCertificate: reloader.GetCertificate,
+		NextProtos:     []string{"h2", "http/1.1"},
+	}
+
+	if err = configureClientAuth(cfg, tlsConfig); err != nil {
+		return nil, nil, err
+	}
+
+	return tlsConfig, reloader, nil
+}
+
+func parseVersion(version string) (uint16, error) {
+	switch version {
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("%w: %q", ErrUnknownTLSVersion, version)
+	}
+}
+
+func parseCipherSuites(names []string) ([]uint16, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	known := make(map[string]uint16, len(tls.CipherSuites()))
+	for _, suite := range tls.CipherSuites() {
+		known[suite.Name] = suite.ID
+	}
+
+	ids := make([]uint16, 0, len(names))
+
+	for _, name := range names {
+		id, ok := known[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownCipherSuite, name)
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func configureClientAuth(cfg config.Config, tlsConfig *tls.Config) error {
+	caFile := cfg.GetString("webService.tls.clientAuth.caFile")
+	if caFile == "" {
+		return nil
+	}
+
+	caBundle, err := os.ReadFile(caFile)
+	if err != nil {
+		return fmt.Errorf("read client CA bundle %q: %w", caFile, err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caBundle) {
+		return fmt.Errorf("%w: %q", ErrNoClientCA, caFile)
+	}
+
+	tlsConfig.ClientCAs = pool
+
+	switch mode := cfg.GetString("webService.tls.clientAuth.mode"); mode {
+	case "", clientAuthRequire:
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	case clientAuthOptional:
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownClientAuth, mode)
+	}
+
+	return nil
+}
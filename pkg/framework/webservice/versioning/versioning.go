@@ -0,0 +1,94 @@
+package versioning
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+
+	"solid-software.test-task/pkg/framework/config"
+	"solid-software.test-task/pkg/framework/logger"
+)
+
+const (
+	// APIPath is the path prefix of all the API versions.
+	APIPath = "/api"
+)
+
+type (
+	// Version describes the lifecycle of an API version.
+	Version struct {
+		// Name is the version name used in the path, e.g. "v1".
+		Name string
+		// Path is the root path of the version, e.g. "/api/v1".
+		Path string
+		// Deprecated reports whether the version is deprecated.
+		Deprecated bool
+		// Deprecation is the time the version is or was deprecated at. It is zero if the version is not deprecated.
+		Deprecation time.Time
+		// Sunset is the time the version stops being served. It is zero if not scheduled.
+		Sunset time.Time
+		// Link points to the deprecation notes or the migration guide.
+		Link string
+	}
+
+	// Policy holds the lifecycle of the API versions.
+	Policy struct {
+		versions map[string]Version
+	}
+)
+
+// NewPolicy reads the lifecycle of the API versions from the "webService.versions" config section.
+// A version is deprecated once its deprecation time is configured.
+// Versions missing in the config are served as current.
+func NewPolicy(cfg config.Config) Policy {
+	policy := Policy{versions: map[string]Version{}}
+
+	for name, value := range cfg.GetStringMap("webService.versions") {
+		settings := cast.ToStringMap(value)
+		version := New(name)
+		version.Deprecation = parseTime(name, "deprecation", settings["deprecation"])
+		version.Sunset = parseTime(name, "sunset", settings["sunset"])
+		version.Link = cast.ToString(settings["link"])
+		version.Deprecated = !version.Deprecation.IsZero()
+
+		policy.versions[version.Name] = version
+	}
+
+	return policy
+}
+
+// New returns a current version with the given name.
+func New(name string) Version {
+	name = strings.ToLower(name)
+
+	return Version{Name: name, Path: APIPath + "/" + name}
+}
+
+// Version returns the lifecycle of the version with the given name.
+func (p Policy) Version(name string) Version {
+	if version, ok := p.versions[strings.ToLower(name)]; ok {
+		return version
+	}
+
+	return New(name)
+}
+
+func parseTime(version, key string, value any) time.Time {
+	if value == nil {
+		return time.Time{}
+	}
+
+	parsed, err := cast.ToTimeE(value)
+	if err != nil {
+		logger.Default().Warn(
+			"ignoring invalid API version setting",
+			slog.String("version", version), slog.String("key", key),
+			slog.Any("error", fmt.Errorf("parse time: %w", err)),
+		)
+	}
+
+	return parsed
+}
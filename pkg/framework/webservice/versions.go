@@ -0,0 +1,61 @@
+package webservice
+
+import (
+	"sort"
+	"time"
+
+	"github.com/kataras/iris/v12"
+
+	"solid-software.test-task/pkg/framework/webservice/versioning"
+)
+
+type (
+	versionsResponse struct {
+		Versions []versionResponse `json:"versions"`
+	}
+
+	versionResponse struct {
+		Version     string     `json:"version"`
+		Path        string     `json:"path"`
+		Deprecated  bool       `json:"deprecated"`
+		Deprecation *time.Time `json:"deprecation,omitempty"`
+		Sunset      *time.Time `json:"sunset,omitempty"`
+		Link        string     `json:"link,omitempty"`
+	}
+)
+
+// registerVersionsEndpoint serves the list of the mounted API versions at the API path.
+func (w *webService) registerVersionsEndpoint() {
+	// the versions are mounted once the endpoints are registered, before the service starts listening.
+	w.application.Get(versioning.APIPath, func(irisCtx iris.Context) {
+		response := versionsResponse{Versions: make([]versionResponse, 0, len(w.versions))}
+		for _, version := range w.versions {
+			response.Versions = append(response.Versions, toVersionResponse(version))
+		}
+
+		sort.Slice(response.Versions, func(i, j int) bool {
+			return response.Versions[i].Version < response.Versions[j].Version
+		})
+
+		_ = irisCtx.JSON(response)
+	})
+}
+
+func toVersionResponse(version versioning.Version) versionResponse {
+	response := versionResponse{
+		Version:    version.Name,
+		Path:       version.Path,
+		Deprecated: version.Deprecated,
+		Link:       version.Link,
+	}
+
+	if !version.Deprecation.IsZero() {
+		response.Deprecation = &version.Deprecation
+	}
+
+	if !version.Sunset.IsZero() {
+		response.Sunset = &version.Sunset
+	}
+
+	return response
+}
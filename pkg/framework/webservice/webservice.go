@@ -2,24 +2,68 @@ package webservice
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
-	"log"
+	"slices"
+	"time"
 
 	"github.com/kataras/iris/v12"
 	"github.com/kataras/iris/v12/core/router"
 
 	"solid-software.test-task/pkg/framework/config"
+	"solid-software.test-task/pkg/framework/lifecycle"
+	"solid-software.test-task/pkg/framework/logger"
+	"solid-software.test-task/pkg/framework/webservice/compression"
+	"solid-software.test-task/pkg/framework/webservice/cors"
 	"solid-software.test-task/pkg/framework/webservice/di"
+	"solid-software.test-task/pkg/framework/webservice/httpcache"
+	"solid-software.test-task/pkg/framework/webservice/idempotency"
 	"solid-software.test-task/pkg/framework/webservice/interfaces"
+	"solid-software.test-task/pkg/framework/webservice/jwt"
+	"solid-software.test-task/pkg/framework/webservice/maintenance"
 	"solid-software.test-task/pkg/framework/webservice/middleware"
+	"solid-software.test-task/pkg/framework/webservice/openapi"
+	"solid-software.test-task/pkg/framework/webservice/ratelimit"
+	"solid-software.test-task/pkg/framework/webservice/requestbody"
 	"solid-software.test-task/pkg/framework/webservice/route"
+	"solid-software.test-task/pkg/framework/webservice/timeout"
+	"solid-software.test-task/pkg/framework/webservice/versioning"
 )
 
 type (
 	webService struct {
 		application *iris.Application
 		config      config.Config
+		lifecycle   *lifecycle.Lifecycle
+		tlsConfig   *tls.Config
+		// rateLimitStore keeps the rate limit buckets of all the routes.
+		rateLimitStore ratelimit.Store
+		// idempotencyStore keeps the idempotency keys with the stored responses.
+		idempotencyStore idempotency.Store
+		// denylist keeps the revoked access tokens.
+		denylist jwt.Denylist
+		// apiDocs collects the registered endpoints into the API document. It is nil if the documentation is disabled.
+		apiDocs *openapi.Builder
+		// versions holds the mounted API versions.
+		versions map[string]versioning.Version
+		// routeRegistry collects the mounted endpoints.
+		routeRegistry *route.Registry
+		// endpointResolvers set the effective settings following the config reloads, keyed by the endpoint method and path.
+		endpointResolvers map[string]func(*route.Endpoint)
 	}
+
+	// routePolicies holds the effective policies of a route.
+	routePolicies struct {
+		timeout      timeout.Policy
+		rateLimit    func() ratelimit.Policy
+		cacheControl httpcache.Policy
+		requestBody  requestbody.Policy
+	}
+)
+
+const (
+	defaultShutdownTimeout = 15 * time.Second
 )
 
 var (
@@ -29,92 +73,291 @@ var (
 
 // New returns a new instance of the web service.
 // It initializes the web application if not already done so.
-func New(cfg config.Config) interfaces.WebService {
-	return &webService{
-		application: initializeWebApp(),
-		config:      cfg,
+func New(cfg config.Config, idempotencyStore idempotency.Store, denylist jwt.Denylist) interfaces.WebService {
+	rateLimitStore := ratelimit.NewMemoryStore()
+
+	service := &webService{
+		application:       initializeWebApp(cfg),
+		config:            cfg,
+		lifecycle:         lifecycle.New(),
+		rateLimitStore:    rateLimitStore,
+		idempotencyStore:  idempotencyStore,
+		denylist:          denylist,
+		versions:          map[string]versioning.Version{},
+		routeRegistry:     route.NewRegistry(),
+		endpointResolvers: map[string]func(*route.Endpoint){},
+	}
+	service.apiDocs = service.newAPIDocsBuilder()
+	service.lifecycle.Go("rate limit store sweeper", rateLimitStore.Sweep)
+	service.lifecycle.Go("token denylist sweeper", jwt.DenylistSweeper(denylist))
+
+	maintenance.Configure(cfg)
+	service.lifecycle.Go("maintenance signals", maintenance.SignalWatcher)
+	service.lifecycle.Go("config watcher", config.Watch)
+
+	if idempotency.NewPolicy(cfg).Enabled {
+		service.lifecycle.Go("idempotency key sweeper", idempotency.Sweeper(idempotencyStore))
 	}
+
+	service.registerMetricsEndpoint()
+	service.registerAPIDocsEndpoints()
+	service.registerVersionsEndpoint()
+
+	return service
 }
 
-func initializeWebApp() *iris.Application {
+func initializeWebApp(cfg config.Config) *iris.Application {
 	app := iris.New()
 	app.SetRegisterRule(iris.RouteError)
-	setUpMiddleware(app)
-	disposeWebAppOnInterrupt(app)
+	setUpMiddleware(app, cfg)
 
 	return app
 }
 
-func setUpMiddleware(app *iris.Application) {
+func setUpMiddleware(app *iris.Application, cfg config.Config) {
+	// the client address, the request ID, the trace span, the access log and the metrics are handled before routing,
+	// so even unmatched requests are covered.
+	app.UseRouter(
+		middleware.ForwardedForHandler(), middleware.RequestIDHandler(), middleware.TracingHandler(),
+		middleware.LoggerHandler(), middleware.MetricsHandler(),
+	)
 	app.UseGlobal(
-		middleware.RecoveryHandler(), middleware.LoggerHandler(),
-		// TODO: implement cors if needed in your infrastructure
-		// cors.New().Handler()
+		middleware.RecoveryHandler(), middleware.ClientCertificateHandler(),
+		middleware.CompressionHandler(compression.NewPolicy(cfg)),
 	)
 }
 
-func disposeWebAppOnInterrupt(app *iris.Application) {
-	iris.RegisterOnInterrupt(
-		func() {
-			if err := app.Shutdown(context.TODO()); err != nil {
-				log.Printf("web application shutdown error: %v", err.Error())
+// RegisterEndpoints registers the endpoints for the web service.
+// Every route is mounted under the root of each API version it serves.
+// Every route gets its own party, so the route specific middleware runs before the authentication.
+// The mounted endpoints are collected in the route registry, listed at "/_routes" of the default version.
+// The CORS and rate limit policies follow the config reloads.
+func (w *webService) RegisterEndpoints(routes ...route.Route) {
+	jwtService := di.InitializeJWTService()
+	corsPolicy := config.NewWatched(w.config, "webService.cors", cors.NewPolicy)
+	rateLimitPolicy := config.NewWatched(w.config, "webService.rateLimit", ratelimit.NewPolicy)
+	versionPolicy := versioning.NewPolicy(w.config)
+	idempotencyPolicy := idempotency.NewPolicy(w.config)
+	cachePolicy := httpcache.NewPolicy(w.config)
+	timeoutPolicy := timeout.NewPolicy(w.config)
+	requestBodyPolicy := requestbody.NewPolicy(w.config)
+	versionRoutes := map[string]router.Party{}
+
+	for _, r := range w.withRoutesEndpoint(routes) {
+		metadata := route.MetadataOf(r)
+		settings := metadata.Settings
+		corsHandler := middleware.CORSHandler(func() cors.Policy {
+			return corsPolicy.Load().Merge(settings.CORS)
+		})
+		policies := routePolicies{
+			timeout: timeoutPolicy.WithDefault(settings.Timeout),
+			rateLimit: func() ratelimit.Policy {
+				return rateLimitPolicy.Load().WithDefault(metadata.Name, settings.RateLimit)
+			},
+			cacheControl: cachePolicy.WithDefault(settings.CacheControl),
+			requestBody:  requestBodyPolicy.WithDefault(settings.MaxBodySize),
+		}
+
+		for _, versionName := range route.VersionsOf(r) {
+			version := versionPolicy.Version(versionName)
+
+			rootRoute, ok := versionRoutes[version.Name]
+			if !ok {
+				rootRoute = createRootRoute(w, jwtService, version)
+				versionRoutes[version.Name] = rootRoute
+				w.versions[version.Name] = version
 			}
 
-			log.Println("web application graceful shutdown")
-		},
-	)
-}
+			routeParty := rootRoute.Party("/")
+			routeParty.Use(corsHandler, middleware.TimeoutHandler(policies.timeout))
 
-// RegisterEndpoints registers the endpoints for the web service.
-func (w *webService) RegisterEndpoints(routes ...route.Route) {
-	regularRoute, protectedRoute := createRoutes(w)
+			if !metadata.WritableInMaintenance {
+				routeParty.Use(middleware.MaintenanceHandler())
+			}
 
-	for _, r := range routes {
-		if r.IsProtected() {
-			r.InitRoutes(protectedRoute)
-		} else {
-			r.InitRoutes(regularRoute)
+			routeParty.Use(middleware.RequestBodyHandler(policies.requestBody))
+
+			if r.IsProtected() {
+				routeParty.Use(jwtService.GetHandler(), middleware.AuthHandler(jwtService, w.denylist))
+
+				if permissionHandler := middleware.PermissionHandler(metadata.Permissions); permissionHandler != nil {
+					routeParty.Use(permissionHandler)
+				}
+			}
+
+			routeParty.Use(middleware.RateLimitHandler(w.rateLimitStore, policies.rateLimit))
+
+			routeParty.Use(middleware.CacheControlHandler(policies.cacheControl))
+
+			if idempotencyPolicy.Enabled && !settings.DisableIdempotency {
+				routeParty.Use(middleware.IdempotencyHandler(w.idempotencyStore, idempotencyPolicy))
+			}
+
+			if len(metadata.Middleware) > 0 {
+				routeParty.Use(metadata.Middleware...)
+			}
+
+			registeredRoutes := len(w.application.GetRoutes())
+			r.InitRoutes(routeParty)
+
+			newRoutes := w.application.GetRoutes()[registeredRoutes:]
+			if r.IsProtected() {
+				requireEndpointPermissions(metadata, version, newRoutes)
+			}
+
+			w.documentRoutes(r, version, newRoutes)
+			w.registerEndpoints(metadata, r.IsProtected(), version, newRoutes, policies)
+			w.registerPreflightRoutes(newRoutes, corsHandler)
 		}
 	}
 }
 
-func createRoutes(w *webService) (router.Party, router.Party) {
-	regularRoute := w.application.Party("/api/v1").PartyConfigure("/")
-	protectedRoute := regularRoute.Party("/")
+// requireEndpointPermissions checks the permissions the metadata requires from the endpoints of the route
+// right before their handlers, after the ones of the whole route.
+func requireEndpointPermissions(metadata route.Metadata, version versioning.Version, routes []*router.Route) {
+	for _, r := range routes {
+		permissions := metadata.EndpointPermissions[endpointKey(r.Method, relativePath(r, version))]
+
+		permissionHandler := middleware.PermissionHandler(permissions)
+		if permissionHandler == nil {
+			continue
+		}
 
-	initializeJwt(regularRoute, protectedRoute)
+		mainHandler := len(r.Handlers) - 1
+		r.Handlers = slices.Insert(r.Handlers, mainHandler, permissionHandler)
 
-	return regularRoute, protectedRoute
+		if r.MainHandlerIndex >= mainHandler {
+			r.MainHandlerIndex++
+		}
+	}
 }
 
-func initializeJwt(regularRoute, protectedRoute router.Party) {
-	jwtService := di.InitializeJWTService()
+// registerPreflightRoutes registers OPTIONS routes for the paths of the given routes,
+// so CORS preflight requests are answered by the CORS handler of the route without authentication.
+func (w *webService) registerPreflightRoutes(routes []*router.Route, corsHandler iris.Handler) {
+	for _, r := range routes {
+		if r.Method == iris.MethodOptions || w.application.GetRoute(iris.MethodOptions+r.Path) != nil {
+			continue
+		}
 
-	regularRoute.ConfigureContainer(
+		w.application.Options(r.Path, corsHandler).SetName(iris.MethodOptions + r.Path)
+	}
+}
+
+func createRootRoute(w *webService, jwtService jwt.Service, version versioning.Version) router.Party {
+	rootRoute := w.application.Party(version.Path).PartyConfigure("/")
+	if deprecationHandler := middleware.DeprecationHandler(version); deprecationHandler != nil {
+		rootRoute.Use(deprecationHandler)
+	}
+
+	rootRoute.ConfigureContainer(
 		func(container *router.APIContainer) {
 			container.RegisterDependency(jwtService)
 		},
 	)
 
-	protectedRoute.ConfigureContainer(
-		func(container *router.APIContainer) {
-			container.Use(jwtService.GetHandler())
-		},
-	)
+	return rootRoute
 }
 
-// Run starts the web service on port 8080.
-// It returns an error if there is issue in listening on the port.
-func (w *webService) Run() error {
-	return w.tryListen(
-		fmt.Sprintf("%s:%d", w.config.GetString("webService.host"), w.config.GetUint("webService.port")),
-	)
+// OnStart registers a hook executed before the web service starts listening.
+func (w *webService) OnStart(name string, hook lifecycle.Hook) {
+	w.lifecycle.OnStart(name, hook)
+}
+
+// OnStop registers a hook executed after the web service has drained in-flight requests.
+func (w *webService) OnStop(name string, hook lifecycle.Hook) {
+	w.lifecycle.OnStop(name, hook)
+}
+
+// Go registers a background worker which is started and stopped together with the web service.
+func (w *webService) Go(name string, worker lifecycle.Worker) {
+	w.lifecycle.Go(name, worker)
+}
+
+// Run executes the start hooks and starts the web service on the configured listeners.
+// When the context is canceled the service stops accepting new connections,
+// waits for in-flight requests up to the configured drain timeout and executes the stop hooks.
+// It returns an error if there is issue in listening on the port or during the shutdown.
+func (w *webService) Run(ctx context.Context) error {
+	if err := w.prepareTLS(); err != nil {
+		return err
+	}
+
+	if err := w.lifecycle.Start(ctx); err != nil {
+		return errors.Join(fmt.Errorf("start web service: %w", err), w.shutdown())
+	}
+
+	w.application.Configure(iris.WithoutInterruptHandler, iris.WithoutServerError(iris.ErrServerClosed))
+
+	if err := w.application.Build(); err != nil {
+		return errors.Join(fmt.Errorf("build web application: %w", err), w.shutdown())
+	}
+
+	// the hosts are created before serving, so a shutdown requested right away is never missed.
+	servers, err := w.createServers()
+	if err != nil {
+		return errors.Join(err, w.shutdown())
+	}
+
+	listenErr := make(chan error, len(servers))
+
+	for _, s := range servers {
+		go func(s server) {
+			listenErr <- w.tryListen(s)
+		}(s)
+	}
+
+	select {
+	case err = <-listenErr:
+		err = errors.Join(err, w.shutdown())
+
+		return errors.Join(err, waitServers(listenErr, len(servers)-1))
+	case <-ctx.Done():
+		err = w.shutdown()
+
+		return errors.Join(waitServers(listenErr, len(servers)), err)
+	}
 }
 
-func (w *webService) tryListen(addr string) error {
-	if err := w.application.Listen(addr); err != nil {
+func (w *webService) tryListen(s server) error {
+	if err := s.host.Serve(s.listener); err != nil {
 		return fmt.Errorf("listen error: %w", err)
 	}
 
 	return nil
 }
+
+func waitServers(listenErr <-chan error, count int) error {
+	errs := make([]error, 0, count)
+	for i := 0; i < count; i++ {
+		errs = append(errs, <-listenErr)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (w *webService) shutdown() error {
+	timeout := w.config.GetDuration("webService.shutdownTimeout")
+	if timeout <= 0 {
+		timeout = defaultShutdownTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var errs []error
+
+	if err := w.application.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("web application shutdown: %w", err))
+	}
+
+	if err := w.lifecycle.Stop(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("web service stop hooks: %w", err))
+	}
+
+	if len(errs) == 0 {
+		logger.Default().Info("web application graceful shutdown")
+	}
+
+	return errors.Join(errs...)
+}
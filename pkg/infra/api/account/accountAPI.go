@@ -0,0 +1,140 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/core/router"
+
+	"solid-software.test-task/pkg/domain/session"
+	"solid-software.test-task/pkg/domain/user"
+	"solid-software.test-task/pkg/framework/ctxutils"
+	"solid-software.test-task/pkg/framework/logger"
+	"solid-software.test-task/pkg/framework/password"
+	"solid-software.test-task/pkg/framework/validation"
+	"solid-software.test-task/pkg/framework/webservice/route"
+	"solid-software.test-task/pkg/infra/api"
+	"solid-software.test-task/pkg/infra/api/user/di"
+)
+
+type (
+	accountAPI struct {
+		hasher   *password.Hasher
+		sessions session.Service
+	}
+)
+
+var (
+	// ErrNotUserToken is returned when an anonymous token, issued to no user, manages an account.
+	ErrNotUserToken = errors.New("the token is not issued to a user")
+)
+
+// NewAccountAPI creates a new instance of AccountAPI hashing the passwords with the hasher and the sessions of the session service.
+func NewAccountAPI(hasher *password.Hasher, sessions session.Service) route.Route {
+	return &accountAPI{hasher: hasher, sessions: sessions}
+}
+
+// IsProtected returns true, as the account is the one of the authenticated user.
+func (*accountAPI) IsProtected() bool {
+	return true
+}
+
+// Metadata names the route; any authenticated user manages their own account.
+func (*accountAPI) Metadata() route.Metadata {
+	return route.Metadata{
+		Name:        "account",
+		Description: "Manages the account of the authenticated user.",
+	}
+}
+
+// InitRoutes inits the account API routes.
+func (a *accountAPI) InitRoutes(party router.Party) {
+	party.Party("/account").ConfigureContainer(
+		func(container *router.APIContainer) {
+			container.RegisterDependency(a.hasher)
+			container.RegisterDependency(di.InitializeUserService)
+			container.RegisterDependency(a.sessions)
+
+			container.Put("/password", handleChangePassword)
+		},
+	)
+}
+
+// Operations describes the account API endpoints.
+func (*accountAPI) Operations() []route.Operation {
+	return []route.Operation{
+		{
+			Method:  iris.MethodPut,
+			Path:    "/account/password",
+			Summary: "Change the password",
+			Description: "Replaces the password of the authenticated user after verifying the current one. " +
+				"All the sessions of the user end and the access tokens issued so far are revoked, this one included.",
+			Tags:    []string{"account"},
+			Request: user.PasswordChange{},
+			Status:  iris.StatusNoContent,
+		},
+	}
+}
+
+func handleChangePassword(
+	irisCtx iris.Context, ctx context.Context, userService user.Service, sessionService session.Service,
+) {
+	userID, err := subjectUserID(ctx)
+	if err != nil {
+		api.HandleError(irisCtx, iris.StatusForbidden, err)
+
+		return
+	}
+
+	var change user.PasswordChange
+
+	if err = api.ReadBody(irisCtx, &change); err != nil {
+		api.HandleError(irisCtx, api.RequestErrorStatus(err), err)
+
+		return
+	}
+
+	if err = validation.Validate(&change); err != nil {
+		api.HandleError(irisCtx, iris.StatusBadRequest, fmt.Errorf("validate password change: %w", err))
+
+		return
+	}
+
+	err = userService.ChangePassword(ctx, userID, change)
+
+	switch {
+	case err == nil:
+		// the password has been changed anyway, so the failure to end the sessions doesn't fail the request.
+		if err = sessionService.EndAll(ctx, userID); err != nil {
+			logger.FromContext(ctx).Error("end sessions after password change",
+				slog.Uint64("userID", uint64(userID)), slog.Any("error", err))
+		}
+
+		irisCtx.StatusCode(iris.StatusNoContent)
+	case errors.Is(err, user.ErrInvalidCredentials), errors.Is(err, password.ErrWeak):
+		api.HandleError(irisCtx, iris.StatusBadRequest, err)
+	case errors.Is(err, user.ErrNoCredentials):
+		api.HandleError(irisCtx, iris.StatusConflict, err)
+	default:
+		api.HandleError(irisCtx, iris.StatusInternalServerError, fmt.Errorf("changing password: %w", err))
+	}
+}
+
+// subjectUserID returns the ID of the user the token is issued to.
+func subjectUserID(ctx context.Context) (uint, error) {
+	subject, ok := ctxutils.Subject(ctx)
+	if !ok {
+		return 0, ErrNotUserToken
+	}
+
+	userID, err := strconv.ParseUint(subject, 10, 0)
+	if err != nil {
+		return 0, fmt.Errorf("%w: parse subject: %w", ErrNotUserToken, err)
+	}
+
+	return uint(userID), nil
+}
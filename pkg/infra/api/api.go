@@ -1,7 +1,22 @@
 package api
 
 import (
+	"context"
+	"errors"
+	"log/slog"
+
 	"github.com/kataras/iris/v12"
+
+	"solid-software.test-task/pkg/framework/ctxutils"
+	"solid-software.test-task/pkg/framework/logger"
+	"solid-software.test-task/pkg/framework/validation"
+)
+
+const (
+	// ProblemRequestIDKey is the problem details member carrying the request ID.
+	ProblemRequestIDKey = "requestId"
+	// ProblemInvalidParamsKey is the problem details member listing the fields which failed the validation.
+	ProblemInvalidParamsKey = "invalid-params"
 )
 
 // HandleError handles errors and sets HTTP status code.
@@ -10,7 +25,21 @@ func HandleError(ctx iris.Context, errorStatus int, err error) {
 		problem := iris.NewProblem().
 			Type(ctx.Request().RequestURI)
 
-		if errorStatus < 500 {
+		if requestID, ok := ctxutils.RequestID(ctx.Request().Context()); ok {
+			problem.Key(ProblemRequestIDKey, requestID)
+		}
+
+		var validationErr *validation.Error
+		if errors.As(err, &validationErr) {
+			problem.Key(ProblemInvalidParamsKey, validationErr.InvalidParams)
+		}
+
+		// a request which exceeded its deadline fails with whatever error the canceled operation returned.
+		if errors.Is(ctx.Request().Context().Err(), context.DeadlineExceeded) {
+			errorStatus = iris.StatusGatewayTimeout
+		}
+
+		if errorStatus < 500 || errorStatus == iris.StatusServiceUnavailable || errorStatus == iris.StatusGatewayTimeout {
 			logAndHandleError(ctx, problem, err, errorStatus)
 		} else {
 			logAndHandleError(ctx, problem, err, iris.StatusInternalServerError)
@@ -22,6 +51,6 @@ func logAndHandleError(ctx iris.Context, problem iris.Problem, err error, status
 	problem.DetailErr(err).Validate()
 
 	if e := ctx.StopWithProblem(statusCode, problem); e != nil {
-		ctx.Application().Logger().Errorf("error while stopping with problem: %v", e)
+		logger.FromContext(ctx.Request().Context()).Error("error while stopping with problem", slog.Any("error", e))
 	}
 }
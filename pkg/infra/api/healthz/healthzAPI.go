@@ -4,6 +4,7 @@ import (
 	"github.com/kataras/iris/v12"
 	"github.com/kataras/iris/v12/core/router"
 
+	"solid-software.test-task/pkg/framework/webservice/maintenance"
 	"solid-software.test-task/pkg/framework/webservice/route"
 	"solid-software.test-task/pkg/infra/api"
 )
@@ -27,8 +28,30 @@ func (*healthz) InitRoutes(party router.Party) {
 	party.Get("/healthz", handlerHealthz)
 }
 
+// Operations describes the healthz API endpoints.
+func (*healthz) Operations() []route.Operation {
+	return []route.Operation{
+		{
+			Method:              iris.MethodGet,
+			Path:                "/healthz",
+			Summary:             "Check the service health",
+			Description:         `Answers "Ok", or "Maintenance" with its message while the service is read-only.`,
+			Tags:                []string{"health"},
+			Response:            "",
+			ResponseContentType: "text/plain",
+		},
+	}
+}
+
+// handlerHealthz answers "Ok", or "Maintenance" with the maintenance message while the service is read-only.
+// The status stays 200 in the maintenance mode, as the service keeps answering the reads.
 func handlerHealthz(irisContext iris.Context) {
-	_, err := irisContext.WriteString("Ok")
+	health := "Ok"
+	if status := maintenance.Current(); status.Enabled {
+		health = "Maintenance: " + status.Message
+	}
+
+	_, err := irisContext.WriteString(health)
 	if err != nil {
 		api.HandleError(irisContext, iris.StatusInternalServerError, err)
 	}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/kataras/iris/v12"
+
+	"solid-software.test-task/pkg/framework/webservice/codec"
+	"solid-software.test-task/pkg/framework/webservice/httpcache"
+	"solid-software.test-task/pkg/framework/webservice/requestbody"
+)
+
+const (
+	headerAccept       = "Accept"
+	headerContentType  = "Content-Type"
+	headerETag         = "ETag"
+	headerLastModified = "Last-Modified"
+	headerVary         = "Vary"
+)
+
+// Respond writes the response in the media type negotiated from the Accept header.
+// It answers with the 406 problem if none of the accepted media types can represent the response.
+// Successful GET responses of resources implementing httpcache.Revisioned carry the ETag and Last-Modified headers,
+// and conditional requests matching them are answered with 304 Not Modified without encoding the body.
+func Respond(ctx iris.Context, status int, response any) {
+	ctx.ResponseWriter().Header().Add(headerVary, headerAccept)
+
+	responseCodec, err := codec.Default.Negotiate(ctx.GetHeader(headerAccept), response)
+	if err != nil {
+		HandleError(ctx, iris.StatusNotAcceptable, err)
+
+		return
+	}
+
+	if isSafeMethod(ctx.Method()) && status == iris.StatusOK {
+		if validator, ok := httpcache.ValidatorOf(response, responseCodec.MediaType()); ok {
+			ctx.Header(headerETag, validator.ETag)
+
+			if !validator.LastModified.IsZero() {
+				ctx.Header(headerLastModified, validator.LastModified.UTC().Format(http.TimeFormat))
+			}
+
+			if validator.NotModified(ctx.Request().Header) {
+				ctx.StatusCode(iris.StatusNotModified)
+
+				return
+			}
+		}
+	}
+
+	// the body is encoded before writing, so an encoding error can still be answered with a problem.
+	var body bytes.Buffer
+	if err = responseCodec.Encode(&body, response); err != nil {
+		HandleError(ctx, iris.StatusInternalServerError, err)
+
+		return
+	}
+
+	ctx.ContentType(responseCodec.MediaType())
+	ctx.StatusCode(status)
+
+	if _, err = ctx.Write(body.Bytes()); err != nil {
+		HandleError(ctx, iris.StatusInternalServerError, fmt.Errorf("write response: %w", err))
+	}
+}
+
+func isSafeMethod(method string) bool {
+	return method == iris.MethodGet || method == iris.MethodHead
+}
+
+// ReadBody decodes the request body of the Content-Type media type into the value.
+// Use RequestErrorStatus to get the status code of the returned error.
+func ReadBody(ctx iris.Context, v any) error {
+	requestCodec, err := codec.Default.ForContentType(ctx.GetHeader(headerContentType))
+	if err != nil {
+		return fmt.Errorf("read request body: %w", err)
+	}
+
+	if err = requestCodec.Decode(ctx.Request().Body, v); err != nil {
+		return fmt.Errorf("parse request body: %w", err)
+	}
+
+	return nil
+}
+
+// RequestErrorStatus returns the status code of the ReadBody error:
+// 413 for a body exceeding its size limit, 415 for an unsupported media type, 400 otherwise.
+func RequestErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, requestbody.ErrTooLarge):
+		return iris.StatusRequestEntityTooLarge
+	case errors.Is(err, codec.ErrUnsupportedMediaType):
+		return iris.StatusUnsupportedMediaType
+	}
+
+	return iris.StatusBadRequest
+}
@@ -0,0 +1,21 @@
+//go:build wireinject
+// +build wireinject
+
+package di
+
+import (
+	"github.com/anhro/wire"
+
+	"solid-software.test-task/pkg/domain/session"
+	"solid-software.test-task/pkg/framework/config"
+	"solid-software.test-task/pkg/infra/db"
+	"solid-software.test-task/pkg/infra/db/denyliststore"
+)
+
+func InitializeSessionService() session.Service {
+	wire.Build(
+		session.NewService, db.GetRawDBConnection, denyliststore.New,
+		config.NewRefreshTokenSection, config.NewJWTSection, config.NewConfig,
+	)
+	return nil
+}
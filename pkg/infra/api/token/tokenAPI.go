@@ -1,22 +1,70 @@
 package token
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+
 	"github.com/brianvoe/gofakeit/v6"
 	"github.com/kataras/iris/v12"
 	"github.com/kataras/iris/v12/core/router"
 
+	"solid-software.test-task/pkg/domain/session"
+	"solid-software.test-task/pkg/domain/user"
+	"solid-software.test-task/pkg/framework/config"
+	"solid-software.test-task/pkg/framework/logger"
+	"solid-software.test-task/pkg/framework/password"
+	"solid-software.test-task/pkg/framework/validation"
 	"solid-software.test-task/pkg/framework/webservice/jwt"
 	"solid-software.test-task/pkg/framework/webservice/route"
 	"solid-software.test-task/pkg/infra/api"
+	"solid-software.test-task/pkg/infra/api/user/di"
+)
+
+const (
+	tokenTypeBearer = "Bearer"
+	secondsInMinute = 60
 )
 
 type (
-	tokenAPI struct{}
+	tokenAPI struct {
+		hasher   *password.Hasher
+		sessions session.Service
+	}
+
+	// tokenResponse is the OAuth 2.0 style access token response.
+	tokenResponse struct {
+		AccessToken string `json:"accessToken" xml:"accessToken"`
+		TokenType   string `json:"tokenType" xml:"tokenType"`
+		// ExpiresIn is the lifetime of the access token in seconds.
+		ExpiresIn int64 `json:"expiresIn" xml:"expiresIn"`
+		// RefreshToken is exchanged for the next access and refresh tokens by POST /token/refresh.
+		RefreshToken string `json:"refreshToken" xml:"refreshToken"`
+	}
+
+	// refreshRequest exchanges the refresh token for the next access and refresh tokens.
+	refreshRequest struct {
+		RefreshToken string `json:"refreshToken" xml:"refreshToken" validate:"required"`
+	}
+
+	// revokeRequest revokes the refresh token with its session, the access token, or both.
+	revokeRequest struct {
+		RefreshToken string `json:"refreshToken" xml:"refreshToken"`
+		AccessToken  string `json:"accessToken" xml:"accessToken"`
+	}
 )
 
-// NewTokenAPI creates a new instance of TokenAPI.
-func NewTokenAPI() route.Route {
-	return &tokenAPI{}
+var (
+	// ErrNothingToRevoke is returned when the revoke request has neither the refresh nor the access token.
+	ErrNothingToRevoke = errors.New("refreshToken or accessToken is required")
+)
+
+// NewTokenAPI creates a new instance of TokenAPI verifying the passwords with the hasher and the sessions of the session service.
+func NewTokenAPI(hasher *password.Hasher, sessions session.Service) route.Route {
+	return &tokenAPI{hasher: hasher, sessions: sessions}
 }
 
 // IsProtected indicates if the TokenAPI is a protected route.
@@ -24,18 +72,242 @@ func (*tokenAPI) IsProtected() bool {
 	return false
 }
 
+// Metadata keeps the token endpoints working in the maintenance mode,
+// so the clients can log in for the reads, refresh their tokens and log out.
+// The endpoints are not idempotent, so the issued tokens are never stored with the idempotency keys.
+func (*tokenAPI) Metadata() route.Metadata {
+	return route.Metadata{
+		Name:                  "token",
+		Description:           "Issues, refreshes and revokes the tokens.",
+		WritableInMaintenance: true,
+		Settings:              route.Settings{DisableIdempotency: true},
+	}
+}
+
 // InitRoutes inits the token API routes.
-func (*tokenAPI) InitRoutes(party router.Party) {
+// The anonymous token generator is served only if "auth.anonymousTokens" is set.
+func (a *tokenAPI) InitRoutes(party router.Party) {
 	party.Party("/token").ConfigureContainer(
 		func(container *router.APIContainer) {
-			container.Get("/generate", generateToken)
+			container.RegisterDependency(a.hasher)
+			container.RegisterDependency(di.InitializeUserService)
+			container.RegisterDependency(a.sessions)
+
+			container.Post("", handleLogin)
+			container.Post("/refresh", handleRefresh)
+			container.Post("/revoke", handleRevoke)
+
+			if config.Current().Auth.AnonymousTokens {
+				container.Get("/generate", generateToken)
+			}
 		},
 	)
 }
 
+// Operations describes the token API endpoints.
+func (*tokenAPI) Operations() []route.Operation {
+	operations := []route.Operation{
+		{
+			Method:  iris.MethodPost,
+			Path:    "/token",
+			Summary: "Log in",
+			Description: "Exchanges the username and the password for a signed JWT whose subject is the user ID " +
+				"and a refresh token starting a new session. The token is granted the auth.permissions, " +
+				"or the auth.adminPermissions if the username is listed in auth.admins.",
+			Tags:     []string{"token"},
+			Request:  user.Credentials{},
+			Response: tokenResponse{},
+		},
+		{
+			Method:  iris.MethodPost,
+			Path:    "/token/refresh",
+			Summary: "Refresh the tokens",
+			Description: "Exchanges the refresh token for a new access token and the next refresh token of the session. " +
+				"Each refresh token is exchanged once; presenting it again ends the session.",
+			Tags:     []string{"token"},
+			Request:  refreshRequest{},
+			Response: tokenResponse{},
+		},
+		{
+			Method:  iris.MethodPost,
+			Path:    "/token/revoke",
+			Summary: "Revoke the tokens",
+			Description: "Ends the session of the refresh token and denies the access token until it expires, e.g. at logout. " +
+				"The unknown, invalid and expired tokens are ignored.",
+			Tags:    []string{"token"},
+			Request: revokeRequest{},
+			Status:  iris.StatusNoContent,
+		},
+	}
+
+	if config.Current().Auth.AnonymousTokens {
+		operations = append(operations, route.Operation{
+			Method:              iris.MethodGet,
+			Path:                "/token/generate",
+			Summary:             "Generate an access token",
+			Description:         "Issues a signed JWT for a random username. Meant for the local development only.",
+			Tags:                []string{"token"},
+			Response:            "",
+			ResponseContentType: "text/plain",
+		})
+	}
+
+	return operations
+}
+
+func handleLogin(
+	irisContext iris.Context, ctx context.Context,
+	userService user.Service, sessionService session.Service, jwtService jwt.Service,
+) {
+	var credentials user.Credentials
+
+	if err := api.ReadBody(irisContext, &credentials); err != nil {
+		api.HandleError(irisContext, api.RequestErrorStatus(err), err)
+
+		return
+	}
+
+	if err := validation.Validate(&credentials); err != nil {
+		api.HandleError(irisContext, iris.StatusBadRequest, fmt.Errorf("validate credentials: %w", err))
+
+		return
+	}
+
+	authenticated, err := userService.Authenticate(ctx, credentials)
+	if err != nil {
+		if errors.Is(err, user.ErrInvalidCredentials) {
+			api.HandleError(irisContext, iris.StatusUnauthorized, err)
+
+			return
+		}
+
+		handleTokenOperationError(irisContext, err, "failed to authenticate")
+
+		return
+	}
+
+	refreshToken, err := sessionService.Start(ctx, authenticated.ID, credentials.Username)
+	if err != nil {
+		handleTokenOperationError(irisContext, err, "failed to start session")
+
+		return
+	}
+
+	respondTokens(irisContext, jwtService, authenticated.ID, credentials.Username, refreshToken)
+}
+
+func handleRefresh(
+	irisContext iris.Context, ctx context.Context, sessionService session.Service, jwtService jwt.Service,
+) {
+	var request refreshRequest
+
+	if err := api.ReadBody(irisContext, &request); err != nil {
+		api.HandleError(irisContext, api.RequestErrorStatus(err), err)
+
+		return
+	}
+
+	if err := validation.Validate(&request); err != nil {
+		api.HandleError(irisContext, iris.StatusBadRequest, fmt.Errorf("validate refresh request: %w", err))
+
+		return
+	}
+
+	refreshToken, refreshed, err := sessionService.Refresh(ctx, request.RefreshToken)
+	if err != nil {
+		if errors.Is(err, session.ErrInvalidToken) || errors.Is(err, session.ErrTokenReused) {
+			api.HandleError(irisContext, iris.StatusUnauthorized, err)
+
+			return
+		}
+
+		handleTokenOperationError(irisContext, err, "failed to refresh token")
+
+		return
+	}
+
+	respondTokens(irisContext, jwtService, refreshed.UserID, refreshed.Username, refreshToken)
+}
+
+// handleRevoke revokes the tokens the way RFC 7009 does: the invalid tokens are ignored,
+// so the response doesn't tell whether a token has been valid.
+func handleRevoke(
+	irisContext iris.Context, ctx context.Context, sessionService session.Service, jwtService jwt.Service,
+) {
+	var request revokeRequest
+
+	if err := api.ReadBody(irisContext, &request); err != nil {
+		api.HandleError(irisContext, api.RequestErrorStatus(err), err)
+
+		return
+	}
+
+	if request.RefreshToken == "" && request.AccessToken == "" {
+		api.HandleError(irisContext, iris.StatusBadRequest, ErrNothingToRevoke)
+
+		return
+	}
+
+	if request.RefreshToken != "" {
+		if err := sessionService.End(ctx, request.RefreshToken); err != nil {
+			handleTokenOperationError(irisContext, err, "failed to revoke refresh token")
+
+			return
+		}
+	}
+
+	if request.AccessToken != "" {
+		if claim, err := jwtService.ParseToken(request.AccessToken); err == nil {
+			if err = sessionService.RevokeAccessToken(ctx, claim); err != nil {
+				handleTokenOperationError(irisContext, err, "failed to revoke access token")
+
+				return
+			}
+		}
+	}
+
+	irisContext.StatusCode(iris.StatusNoContent)
+}
+
+// respondTokens signs the access token of the user and responds it together with the refresh token.
+func respondTokens(irisContext iris.Context, jwtService jwt.Service, userID uint, username, refreshToken string) {
+	sections := config.Current()
+
+	token, err := jwtService.GetToken(jwt.SampleClaim{
+		Subject:     strconv.FormatUint(uint64(userID), 10),
+		Username:    username,
+		Permissions: loginPermissions(sections.Auth, username),
+	})
+	if err != nil {
+		handleTokenOperationError(irisContext, err, "failed to sign token")
+
+		return
+	}
+
+	// the tokens must not be stored by the caches (RFC 6749, section 5.1).
+	irisContext.Header("Cache-Control", "no-store")
+	api.Respond(irisContext, iris.StatusOK, tokenResponse{
+		AccessToken:  string(token),
+		TokenType:    tokenTypeBearer,
+		ExpiresIn:    sections.WebService.JWT.TokenExpirationTimeInMinutes * secondsInMinute,
+		RefreshToken: refreshToken,
+	})
+}
+
+// loginPermissions returns the permissions granted to the user logging in:
+// the admin permissions to the configured admins and the login permissions to the others.
+func loginPermissions(section config.Auth, username string) []string {
+	if slices.Contains(section.Admins, username) {
+		return section.AdminPermissions
+	}
+
+	return section.Permissions
+}
+
 func generateToken(irisContext iris.Context, jwtService jwt.Service) {
 	sampleClaim := jwt.SampleClaim{
-		Username: gofakeit.Username(),
+		Username:    gofakeit.Username(),
+		Permissions: config.Current().WebService.JWT.Permissions,
 	}
 
 	token, err := jwtService.GetToken(sampleClaim)
@@ -51,6 +323,6 @@ func generateToken(irisContext iris.Context, jwtService jwt.Service) {
 }
 
 func handleTokenOperationError(irisContext iris.Context, err error, message string) {
-	irisContext.Application().Logger().Errorf("%s: %v", message, err)
+	logger.FromContext(irisContext.Request().Context()).Error(message, slog.Any("error", err))
 	api.HandleError(irisContext, iris.StatusInternalServerError, err)
 }
@@ -7,12 +7,17 @@ import (
 	"github.com/anhro/wire"
 
 	"solid-software.test-task/pkg/domain/user"
+	"solid-software.test-task/pkg/framework/config"
+	"solid-software.test-task/pkg/framework/password"
 	"solid-software.test-task/pkg/infra/db"
 )
 
-func InitializeUserService() user.Service {
-	wire.Build(
-		user.NewUserService, db.GetRawDBConnection,
-	)
+func InitializeHasher() *password.Hasher {
+	wire.Build(password.NewHasher, config.NewPasswordSection, config.NewConfig)
+	return nil
+}
+
+func InitializeUserService(hasher *password.Hasher) user.Service {
+	wire.Build(user.NewUserService, db.GetRawDBConnection)
 	return nil
 }
@@ -8,13 +8,22 @@ package di
 
 import (
 	"solid-software.test-task/pkg/domain/user"
+	"solid-software.test-task/pkg/framework/config"
+	"solid-software.test-task/pkg/framework/password"
 	"solid-software.test-task/pkg/infra/db"
 )
 
 // Injectors from initializeUserService.go:
 
-func InitializeUserService() user.Service {
+func InitializeHasher() *password.Hasher {
+	configConfig := config.NewConfig()
+	watched := config.NewPasswordSection(configConfig)
+	hasher := password.NewHasher(watched)
+	return hasher
+}
+
+func InitializeUserService(hasher *password.Hasher) user.Service {
 	gormDB := db.GetRawDBConnection()
-	userService := user.NewUserService(gormDB)
+	userService := user.NewUserService(gormDB, hasher)
 	return userService
 }
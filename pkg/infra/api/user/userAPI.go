@@ -4,24 +4,38 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 
 	"github.com/kataras/iris/v12"
 	"github.com/kataras/iris/v12/core/router"
 
+	"solid-software.test-task/pkg/domain/session"
 	"solid-software.test-task/pkg/domain/user"
+	"solid-software.test-task/pkg/framework/logger"
+	"solid-software.test-task/pkg/framework/password"
+	"solid-software.test-task/pkg/framework/validation"
 	"solid-software.test-task/pkg/framework/webservice/route"
 	"solid-software.test-task/pkg/infra/api"
 	"solid-software.test-task/pkg/infra/api/user/di"
 	"solid-software.test-task/pkg/infra/db"
 )
 
+const (
+	// CredentialsPermission is required to set the credentials of the users, on top of the "users" permission.
+	// It is not granted at login by default, so the users can't take over each other's accounts.
+	CredentialsPermission = "credentials"
+)
+
 type (
-	userAPI struct{}
+	userAPI struct {
+		hasher   *password.Hasher
+		sessions session.Service
+	}
 )
 
-// NewUserAPI creates a new user API.
-func NewUserAPI() route.Route {
-	return &userAPI{}
+// NewUserAPI creates a new user API hashing the passwords with the hasher and the sessions of the session service.
+func NewUserAPI(hasher *password.Hasher, sessions session.Service) route.Route {
+	return &userAPI{hasher: hasher, sessions: sessions}
 }
 
 // IsProtected returns true if the route is protected by authentication.
@@ -29,42 +43,110 @@ func (*userAPI) IsProtected() bool {
 	return true
 }
 
-func (*userAPI) InitRoutes(party router.Party) {
+// Metadata requires the "users" permission, and the CredentialsPermission to set the credentials.
+func (*userAPI) Metadata() route.Metadata {
+	return route.Metadata{
+		Name:        "user",
+		Description: "Manages the users.",
+		Permissions: []string{"users"},
+		EndpointPermissions: map[string][]string{
+			iris.MethodPut + " /user/{id:uint}/credentials": {CredentialsPermission},
+		},
+	}
+}
+
+func (a *userAPI) InitRoutes(party router.Party) {
 	party.Party("/user").ConfigureContainer(
 		func(container *router.APIContainer) {
+			container.RegisterDependency(a.hasher)
 			container.RegisterDependency(di.InitializeUserService)
+			container.RegisterDependency(a.sessions)
 
 			container.Post("/", handleCreateUser)
 			container.Get("s", handelGetUsers)
 			singleUserRoute := container.Party("/{id:uint}")
 			singleUserRoute.Get("", handleGetUser)
 			singleUserRoute.Put("", handleUpdateUser)
+			singleUserRoute.Patch("", handlePatchUser)
 			singleUserRoute.Delete("", handleDeleteUser)
+
+			singleUserRoute.Put("/credentials", handleSetCredentials)
 		},
 	)
 }
 
+// Operations describes the user API endpoints.
+func (*userAPI) Operations() []route.Operation {
+	tags := []string{"user"}
+
+	return []route.Operation{
+		{
+			Method: iris.MethodPost, Path: "/user", Summary: "Create a user", Tags: tags,
+			Request: user.Entity{}, Response: user.Entity{},
+		},
+		{
+			Method: iris.MethodGet, Path: "/users", Summary: "List the users", Tags: tags,
+			Response: []user.Entity{},
+		},
+		{
+			Method: iris.MethodGet, Path: "/user/{id:uint}", Summary: "Get a user", Tags: tags,
+			Response: user.Entity{},
+		},
+		{
+			Method: iris.MethodPut, Path: "/user/{id:uint}", Summary: "Update a user",
+			Description: "The user ID in the body must match the one in the path.", Tags: tags,
+			Request: user.Entity{}, Response: user.Entity{},
+		},
+		{
+			Method: iris.MethodPatch, Path: "/user/{id:uint}", Summary: "Partially update a user",
+			Description: "The fields present in the body replace the stored ones; the result is validated as a whole.",
+			Tags:        tags, Request: user.Entity{}, Response: user.Entity{},
+		},
+		{
+			Method: iris.MethodDelete, Path: "/user/{id:uint}", Summary: "Delete a user",
+			Description: "Ends the sessions of the user and revokes the access tokens issued to the user.", Tags: tags,
+		},
+		{
+			Method: iris.MethodPut, Path: "/user/{id:uint}/credentials", Summary: "Set the user credentials",
+			Description: "Sets the username and the password the user logs in with. The password must satisfy the policy. " +
+				"Requires the \"credentials\" permission. " +
+				"The sessions of the user end and the access tokens issued to the user are revoked.",
+			Tags: tags, Request: user.Credentials{}, Status: iris.StatusNoContent,
+		},
+	}
+}
+
 func handleRequest(irisCtx iris.Context, action func() (any, int, error)) {
 	response, status, err := action()
 	if err != nil {
 		api.HandleError(irisCtx, status, err)
-	} else if err = irisCtx.JSON(response); err != nil {
-		api.HandleError(irisCtx, iris.StatusInternalServerError, err)
+	} else {
+		api.Respond(irisCtx, status, response)
 	}
 }
 
-func readJSONObject(irisCtx iris.Context) (*user.Entity, error) {
+func readUserObject(irisCtx iris.Context) (*user.Entity, int, error) {
 	var userRQ user.Entity
 
-	if err := irisCtx.ReadJSON(&userRQ); err != nil {
-		return nil, fmt.Errorf("parse JSON: %w", err)
+	if status, err := decodeUser(irisCtx, &userRQ); err != nil {
+		return nil, status, err
+	}
+
+	return &userRQ, iris.StatusOK, nil
+}
+
+// decodeUser decodes the request body over the user and validates the result,
+// so the create, update and patch requests share the same rules.
+func decodeUser(irisCtx iris.Context, userRQ *user.Entity) (int, error) {
+	if err := api.ReadBody(irisCtx, userRQ); err != nil {
+		return api.RequestErrorStatus(err), err
 	}
 
-	if userRQ.Name == "" {
-		return nil, fmt.Errorf("username is empty")
+	if err := validation.Validate(userRQ); err != nil {
+		return iris.StatusBadRequest, fmt.Errorf("validate user: %w", err)
 	}
 
-	return &userRQ, nil
+	return iris.StatusOK, nil
 }
 
 func saveUser(ctx context.Context, userService user.Service, userRQ *user.Entity) error {
@@ -77,9 +159,9 @@ func saveUser(ctx context.Context, userService user.Service, userRQ *user.Entity
 
 func handleCreateUser(irisCtx iris.Context, ctx context.Context, userService user.Service) {
 	executeCreateOrUpdateUser := func() (any, int, error) {
-		userRQ, err := readJSONObject(irisCtx)
+		userRQ, status, err := readUserObject(irisCtx)
 		if err != nil {
-			return nil, iris.StatusBadRequest, err
+			return nil, status, err
 		}
 
 		err = saveUser(ctx, userService, userRQ)
@@ -94,9 +176,9 @@ func handleCreateUser(irisCtx iris.Context, ctx context.Context, userService use
 
 func handleUpdateUser(irisCtx iris.Context, ctx context.Context, userService user.Service) {
 	executeCreateOrUpdateUser := func() (any, int, error) {
-		userRQ, err := readJSONObject(irisCtx)
+		userRQ, status, err := readUserObject(irisCtx)
 		if err != nil {
-			return nil, iris.StatusBadRequest, err
+			return nil, status, err
 		}
 
 		userID, err := irisCtx.Params().GetUint("id")
@@ -118,6 +200,40 @@ func handleUpdateUser(irisCtx iris.Context, ctx context.Context, userService use
 	handleRequest(irisCtx, executeCreateOrUpdateUser)
 }
 
+func handlePatchUser(irisCtx iris.Context, ctx context.Context, userService user.Service) {
+	executePatchUser := func() (any, int, error) {
+		userID, err := irisCtx.Params().GetUint("id")
+		if err != nil {
+			return nil, iris.StatusBadRequest, fmt.Errorf("get user ID: %w", err)
+		}
+
+		userRQ, err := userService.GetByID(ctx, userID)
+		if err != nil {
+			if errors.Is(err, db.ErrRecordNotFound) {
+				return nil, iris.StatusNotFound, fmt.Errorf("getting user by ID: %w", err)
+			}
+
+			return nil, iris.StatusInternalServerError, fmt.Errorf("getting user by ID: %w", err)
+		}
+
+		if status, err := decodeUser(irisCtx, userRQ); err != nil {
+			return nil, status, err
+		}
+
+		if userID != userRQ.ID {
+			return nil, iris.StatusBadRequest, fmt.Errorf("user ID in path and in body are not equal")
+		}
+
+		err = saveUser(ctx, userService, userRQ)
+		if err != nil {
+			return nil, iris.StatusInternalServerError, err
+		}
+
+		return userRQ, iris.StatusOK, nil
+	}
+	handleRequest(irisCtx, executePatchUser)
+}
+
 func handelGetUsers(irisCtx iris.Context, ctx context.Context, userService user.Service) {
 	executeGetUsers := func() (any, int, error) {
 		userResponses, err := userService.GetWithFilter(ctx)
@@ -151,13 +267,20 @@ func handleGetUser(irisCtx iris.Context, ctx context.Context, userService user.S
 	handleRequest(irisCtx, executeGetUser)
 }
 
-func handleDeleteUser(irisCtx iris.Context, ctx context.Context, userService user.Service) {
+func handleDeleteUser(
+	irisCtx iris.Context, ctx context.Context, userService user.Service, sessionService session.Service,
+) {
 	executeDeleteUser := func() (any, int, error) {
 		userID, err := irisCtx.Params().GetUint("id")
 		if err != nil {
 			return nil, iris.StatusBadRequest, fmt.Errorf("get user ID: %w", err)
 		}
 
+		err = sessionService.EndAll(ctx, userID)
+		if err != nil {
+			return nil, iris.StatusInternalServerError, fmt.Errorf("ending user sessions: %w", err)
+		}
+
 		err = userService.DeleteByID(ctx, userID)
 		if err != nil {
 			return nil, iris.StatusInternalServerError, fmt.Errorf("deleting user by ID: %w", err)
@@ -167,3 +290,49 @@ func handleDeleteUser(irisCtx iris.Context, ctx context.Context, userService use
 	}
 	handleRequest(irisCtx, executeDeleteUser)
 }
+
+func handleSetCredentials(
+	irisCtx iris.Context, ctx context.Context, userService user.Service, sessionService session.Service,
+) {
+	userID, err := irisCtx.Params().GetUint("id")
+	if err != nil {
+		api.HandleError(irisCtx, iris.StatusBadRequest, fmt.Errorf("get user ID: %w", err))
+
+		return
+	}
+
+	var credentials user.Credentials
+
+	if err = api.ReadBody(irisCtx, &credentials); err != nil {
+		api.HandleError(irisCtx, api.RequestErrorStatus(err), err)
+
+		return
+	}
+
+	if err = validation.Validate(&credentials); err != nil {
+		api.HandleError(irisCtx, iris.StatusBadRequest, fmt.Errorf("validate credentials: %w", err))
+
+		return
+	}
+
+	err = userService.SetCredentials(ctx, userID, credentials)
+
+	switch {
+	case err == nil:
+		// the credentials have been set anyway, so the failure to end the sessions doesn't fail the request.
+		if err = sessionService.EndAll(ctx, userID); err != nil {
+			logger.FromContext(ctx).Error("end sessions after setting credentials",
+				slog.Uint64("userID", uint64(userID)), slog.Any("error", err))
+		}
+
+		irisCtx.StatusCode(iris.StatusNoContent)
+	case errors.Is(err, db.ErrRecordNotFound):
+		api.HandleError(irisCtx, iris.StatusNotFound, fmt.Errorf("setting credentials: %w", err))
+	case errors.Is(err, password.ErrWeak):
+		api.HandleError(irisCtx, iris.StatusBadRequest, err)
+	case errors.Is(err, user.ErrUsernameTaken):
+		api.HandleError(irisCtx, iris.StatusConflict, err)
+	default:
+		api.HandleError(irisCtx, iris.StatusInternalServerError, fmt.Errorf("setting credentials: %w", err))
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"gorm.io/gorm"
 
 	"solid-software.test-task/pkg/infra/db/di"
+	"solid-software.test-task/pkg/infra/db/initializer"
 )
 
 var (
@@ -16,3 +17,8 @@ var (
 func GetRawDBConnection() *gorm.DB {
 	return di.InitializeNewDBConnection().GetRawDBConnection()
 }
+
+// Close closes the DB connection if it has been opened.
+func Close() error {
+	return initializer.CloseDBConnection()
+}
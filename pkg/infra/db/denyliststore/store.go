@@ -0,0 +1,113 @@
+package denyliststore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"solid-software.test-task/pkg/framework/tracing"
+	"solid-software.test-task/pkg/framework/webservice/jwt"
+	"solid-software.test-task/pkg/infra/db/models"
+)
+
+const (
+	tokenIDPrefix = "jti:"
+	subjectPrefix = "sub:"
+)
+
+type (
+	store struct {
+		db *gorm.DB
+	}
+)
+
+// New returns the token denylist keeping the entries in the database,
+// so a revocation takes effect on all the replicas sharing the database.
+func New(db *gorm.DB) jwt.Denylist {
+	return &store{db: db}
+}
+
+// Revoke denies the token with the ID until it expires.
+func (s *store) Revoke(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	ctx, span := tracing.StartSpan(ctx, "Denylist.Revoke")
+	defer span.End()
+
+	return s.upsert(ctx, models.RevokedToken{Target: tokenIDPrefix + tokenID, ExpiresAt: expiresAt})
+}
+
+// RevokeSubject denies the tokens issued to the subject before the given time.
+// The "iat" claim has the second precision, so the tokens issued within the same second are denied too:
+// a login right after the revocation may need to be repeated, but no token issued before it stays valid.
+func (s *store) RevokeSubject(ctx context.Context, subject string, issuedBefore, expiresAt time.Time) error {
+	ctx, span := tracing.StartSpan(ctx, "Denylist.RevokeSubject")
+	defer span.End()
+
+	return s.upsert(ctx, models.RevokedToken{
+		Target:       subjectPrefix + subject,
+		IssuedBefore: issuedBefore.Truncate(time.Second),
+		ExpiresAt:    expiresAt,
+	})
+}
+
+func (s *store) upsert(ctx context.Context, entry models.RevokedToken) error {
+	err := s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "target"}},
+		DoUpdates: clause.AssignmentColumns([]string{"issued_before", "expires_at"}),
+	}).Create(&entry).Error
+	if err != nil {
+		return fmt.Errorf("revoking token: %w", err)
+	}
+
+	return nil
+}
+
+// IsRevoked reports whether the token of the claim is denied by its ID or by its subject.
+func (s *store) IsRevoked(ctx context.Context, claim *jwt.SampleClaim) (bool, error) {
+	ctx, span := tracing.StartSpan(ctx, "Denylist.IsRevoked")
+	defer span.End()
+
+	var targets []string
+
+	if claim.ID != "" {
+		targets = append(targets, tokenIDPrefix+claim.ID)
+	}
+
+	if claim.Subject != "" {
+		targets = append(targets, subjectPrefix+claim.Subject)
+	}
+
+	if len(targets) == 0 {
+		return false, nil
+	}
+
+	var entries []models.RevokedToken
+
+	err := s.db.WithContext(ctx).Where("target IN ? AND expires_at > ?", targets, time.Now()).Find(&entries).Error
+	if err != nil {
+		return false, fmt.Errorf("retrieving denylist entries: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IssuedBefore.IsZero() || !claim.IssuedAt.After(entry.IssuedBefore) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// DeleteExpired removes the entries expired before the given time.
+func (s *store) DeleteExpired(ctx context.Context, now time.Time) error {
+	ctx, span := tracing.StartSpan(ctx, "Denylist.DeleteExpired")
+	defer span.End()
+
+	err := s.db.WithContext(ctx).Where("expires_at < ?", now).Delete(&models.RevokedToken{}).Error
+	if err != nil {
+		return fmt.Errorf("deleting expired denylist entries: %w", err)
+	}
+
+	return nil
+}
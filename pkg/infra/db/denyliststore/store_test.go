@@ -0,0 +1,130 @@
+package denyliststore_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+
+	"solid-software.test-task/pkg/framework/webservice/jwt"
+	"solid-software.test-task/pkg/infra/db/denyliststore"
+	"solid-software.test-task/pkg/infra/db/models"
+)
+
+func TestStoreIsRevoked(t *testing.T) {
+	t.Parallel()
+
+	// revokedAt is within a second, as the revocation happens at an arbitrary time while "iat" has no fraction.
+	revokedAt := time.Now().Truncate(time.Second).Add(400 * time.Millisecond)
+	expiresAt := revokedAt.Add(time.Hour)
+
+	tests := []struct {
+		name   string
+		revoke func(ctx context.Context, denylist jwt.Denylist) error
+		claim  jwt.SampleClaim
+		want   bool
+	}{
+		{
+			name:   "subject token issued a second earlier",
+			revoke: revokeSubject("1", revokedAt, expiresAt),
+			claim:  jwt.SampleClaim{Subject: "1", IssuedAt: revokedAt.Truncate(time.Second).Add(-time.Second)},
+			want:   true,
+		},
+		{
+			name:   "subject token issued within the second of the revocation",
+			revoke: revokeSubject("1", revokedAt, expiresAt),
+			claim:  jwt.SampleClaim{Subject: "1", IssuedAt: revokedAt.Truncate(time.Second)},
+			want:   true,
+		},
+		{
+			name:   "subject token issued the next second",
+			revoke: revokeSubject("1", revokedAt, expiresAt),
+			claim:  jwt.SampleClaim{Subject: "1", IssuedAt: revokedAt.Truncate(time.Second).Add(time.Second)},
+			want:   false,
+		},
+		{
+			name:   "token of another subject",
+			revoke: revokeSubject("1", revokedAt, expiresAt),
+			claim:  jwt.SampleClaim{Subject: "2", IssuedAt: revokedAt.Truncate(time.Second)},
+			want:   false,
+		},
+		{
+			name:   "expired subject entry",
+			revoke: revokeSubject("1", revokedAt, time.Now().Add(-time.Second)),
+			claim:  jwt.SampleClaim{Subject: "1", IssuedAt: revokedAt.Truncate(time.Second)},
+			want:   false,
+		},
+		{
+			name:   "revoked token ID",
+			revoke: revokeToken("jti-1", expiresAt),
+			claim:  jwt.SampleClaim{Subject: "1", ID: "jti-1", IssuedAt: revokedAt.Add(time.Minute)},
+			want:   true,
+		},
+		{
+			name:   "another token ID",
+			revoke: revokeToken("jti-1", expiresAt),
+			claim:  jwt.SampleClaim{Subject: "1", ID: "jti-2", IssuedAt: revokedAt.Add(time.Minute)},
+			want:   false,
+		},
+		{
+			name:   "anonymous token without an ID",
+			revoke: revokeToken("jti-1", expiresAt),
+			claim:  jwt.SampleClaim{IssuedAt: revokedAt},
+			want:   false,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			denylist := denyliststore.New(openDB(t))
+
+			if err := test.revoke(ctx, denylist); err != nil {
+				t.Fatalf("revoking error = %v", err)
+			}
+
+			got, err := denylist.IsRevoked(ctx, &test.claim)
+			if err != nil {
+				t.Fatalf("IsRevoked() error = %v", err)
+			}
+
+			if got != test.want {
+				t.Errorf("IsRevoked() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func revokeSubject(subject string, issuedBefore, expiresAt time.Time) func(context.Context, jwt.Denylist) error {
+	return func(ctx context.Context, denylist jwt.Denylist) error {
+		return denylist.RevokeSubject(ctx, subject, issuedBefore, expiresAt)
+	}
+}
+
+func revokeToken(tokenID string, expiresAt time.Time) func(context.Context, jwt.Denylist) error {
+	return func(ctx context.Context, denylist jwt.Denylist) error {
+		return denylist.Revoke(ctx, tokenID, expiresAt)
+	}
+}
+
+func openDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("opening database: %v", err)
+	}
+
+	if err = db.AutoMigrate(&models.RevokedToken{}); err != nil {
+		t.Fatalf("migrating database: %v", err)
+	}
+
+	return db
+}
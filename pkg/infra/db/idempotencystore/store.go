@@ -0,0 +1,172 @@
+package idempotencystore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"solid-software.test-task/pkg/framework/tracing"
+	"solid-software.test-task/pkg/framework/webservice/idempotency"
+	"solid-software.test-task/pkg/infra/db/models"
+)
+
+type (
+	store struct {
+		db *gorm.DB
+	}
+)
+
+// New returns the idempotency store keeping the keys in the database.
+// A key is reserved by inserting its row, so concurrent retries are serialized by the primary key
+// also across the replicas sharing the database.
+func New(db *gorm.DB) idempotency.Store {
+	return &store{db: db}
+}
+
+// Acquire reserves the key for the request.
+func (s *store) Acquire(ctx context.Context, lock idempotency.Lock) (*idempotency.Response, error) {
+	ctx, span := tracing.StartSpan(ctx, "IdempotencyStore.Acquire")
+	defer span.End()
+
+	record := models.IdempotencyKey{
+		ScopedKey:   lock.Key,
+		Fingerprint: lock.Fingerprint,
+		LockedUntil: lock.LockedUntil,
+		ExpiresAt:   lock.ExpiresAt,
+	}
+
+	result := s.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&record)
+	if result.Error != nil {
+		return nil, fmt.Errorf("inserting idempotency key: %w", result.Error)
+	}
+
+	if result.RowsAffected == 1 {
+		return nil, nil //nolint:nilnil
+	}
+
+	acquired, err := s.takeOver(ctx, lock)
+	if err != nil || acquired {
+		return nil, err
+	}
+
+	var existing models.IdempotencyKey
+
+	err = s.db.WithContext(ctx).First(&existing, "scoped_key = ?", lock.Key).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// the key has been released meanwhile, so its request may be retried.
+			return nil, idempotency.ErrKeyInUse
+		}
+
+		return nil, fmt.Errorf("retrieving idempotency key: %w", err)
+	}
+
+	switch {
+	case existing.Fingerprint != lock.Fingerprint:
+		return nil, idempotency.ErrKeyMismatch
+	case !existing.Completed:
+		return nil, idempotency.ErrKeyInUse
+	}
+
+	return toResponse(&existing)
+}
+
+// takeOver reserves the key if it has expired or its reservation has been abandoned.
+// The conditional update lets only one of the concurrent retries take the key over.
+func (s *store) takeOver(ctx context.Context, lock idempotency.Lock) (bool, error) {
+	now := time.Now()
+
+	result := s.db.WithContext(ctx).
+		Model(&models.IdempotencyKey{}).
+		Where("scoped_key = ? AND (expires_at < ? OR (completed = ? AND locked_until < ?))", lock.Key, now, false, now).
+		Updates(map[string]any{
+			"fingerprint":  lock.Fingerprint,
+			"completed":    false,
+			"status_code":  0,
+			"header":       "",
+			"body":         nil,
+			"locked_until": lock.LockedUntil,
+			"expires_at":   lock.ExpiresAt,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("taking over idempotency key: %w", result.Error)
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// Complete stores the response of the request holding the key.
+func (s *store) Complete(ctx context.Context, lock idempotency.Lock, response idempotency.Response) error {
+	ctx, span := tracing.StartSpan(ctx, "IdempotencyStore.Complete")
+	defer span.End()
+
+	header, err := json.Marshal(response.Header)
+	if err != nil {
+		return fmt.Errorf("encoding response header: %w", err)
+	}
+
+	err = s.db.WithContext(ctx).
+		Model(&models.IdempotencyKey{}).
+		Where("scoped_key = ? AND fingerprint = ? AND completed = ?", lock.Key, lock.Fingerprint, false).
+		Updates(map[string]any{
+			"completed":   true,
+			"status_code": response.StatusCode,
+			"header":      string(header),
+			"body":        response.Body,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("storing idempotent response: %w", err)
+	}
+
+	return nil
+}
+
+// Release removes the reservation of the key.
+func (s *store) Release(ctx context.Context, lock idempotency.Lock) error {
+	ctx, span := tracing.StartSpan(ctx, "IdempotencyStore.Release")
+	defer span.End()
+
+	err := s.db.WithContext(ctx).
+		Where("scoped_key = ? AND fingerprint = ? AND completed = ?", lock.Key, lock.Fingerprint, false).
+		Delete(&models.IdempotencyKey{}).Error
+	if err != nil {
+		return fmt.Errorf("releasing idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteExpired removes the keys expired before the given time.
+func (s *store) DeleteExpired(ctx context.Context, now time.Time) error {
+	ctx, span := tracing.StartSpan(ctx, "IdempotencyStore.DeleteExpired")
+	defer span.End()
+
+	err := s.db.WithContext(ctx).Where("expires_at < ?", now).Delete(&models.IdempotencyKey{}).Error
+	if err != nil {
+		return fmt.Errorf("deleting expired idempotency keys: %w", err)
+	}
+
+	return nil
+}
+
+func toResponse(record *models.IdempotencyKey) (*idempotency.Response, error) {
+	response := idempotency.Response{StatusCode: record.StatusCode, Body: record.Body}
+
+	if record.Header != "" {
+		if err := json.Unmarshal([]byte(record.Header), &response.Header); err != nil {
+			return nil, fmt.Errorf("decoding response header: %w", err)
+		}
+	}
+
+	if response.Header == nil {
+		response.Header = http.Header{}
+	}
+
+	return &response, nil
+}
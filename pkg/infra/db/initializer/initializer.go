@@ -7,6 +7,8 @@ import (
 	"github.com/glebarez/sqlite"
 	"gorm.io/gorm"
 
+	"solid-software.test-task/pkg/framework/metrics"
+	"solid-software.test-task/pkg/framework/tracing"
 	"solid-software.test-task/pkg/infra/db/interfaces"
 	"solid-software.test-task/pkg/infra/db/models"
 )
@@ -44,10 +46,21 @@ func InitDBConnection() interfaces.Connection {
 				panic(fmt.Errorf("open db connection: %w", err))
 			}
 
+			if err = registerMetrics(dbConnection); err != nil {
+				panic(err)
+			}
+
+			if err = dbConnection.Use(tracing.NewGormPlugin()); err != nil {
+				panic(fmt.Errorf("use tracing plugin: %w", err))
+			}
+
 			_dbConnection = dbConnection
 			// TODO: for right db migration must be used github.com/pressly/goose or something like this.
 			//  but for this test task it's not necessary
-			err = migrateDBModels(_dbConnection, &models.User{})
+			err = migrateDBModels(
+				_dbConnection, &models.User{}, &models.Credential{}, &models.IdempotencyKey{},
+				&models.RefreshToken{}, &models.RevokedToken{},
+			)
 			if err != nil {
 				panic(err)
 			}
@@ -57,6 +70,41 @@ func InitDBConnection() interfaces.Connection {
 	return &connectionImpl{_dbConnection}
 }
 
+// CloseDBConnection closes the db connection if it has been initialized.
+func CloseDBConnection() error {
+	if _dbConnection == nil {
+		return nil
+	}
+
+	sqlDB, err := _dbConnection.DB()
+	if err != nil {
+		return fmt.Errorf("get sql db: %w", err)
+	}
+
+	if err = sqlDB.Close(); err != nil {
+		return fmt.Errorf("close db connection: %w", err)
+	}
+
+	return nil
+}
+
+func registerMetrics(db *gorm.DB) error {
+	if err := db.Use(metrics.NewGormPlugin()); err != nil {
+		return fmt.Errorf("use metrics plugin: %w", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("get sql db: %w", err)
+	}
+
+	if err = metrics.RegisterDBStats(sqlDB, "gorm"); err != nil {
+		return fmt.Errorf("register db stats metrics: %w", err)
+	}
+
+	return nil
+}
+
 func migrateDBModels(db *gorm.DB, dbModels ...any) error {
 	if err := db.Migrator().AutoMigrate(dbModels...); err != nil {
 		return fmt.Errorf("migrate db models: %w", err)
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+)
+
+type (
+	// Credential struct represents the login credentials of a user in the database.
+	Credential struct {
+		UserID       uint   `gorm:"primaryKey;autoIncrement:false"`
+		Username     string `gorm:"uniqueIndex;size:64"`
+		PasswordHash string
+		CreatedAt    time.Time
+		UpdatedAt    time.Time
+	}
+)
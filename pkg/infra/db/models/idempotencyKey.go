@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+)
+
+type (
+	// IdempotencyKey struct represents an idempotency key with the stored response of its request in the database.
+	IdempotencyKey struct {
+		// ScopedKey is the Idempotency-Key header value prefixed with the principal.
+		ScopedKey   string `gorm:"primaryKey"`
+		Fingerprint string
+		Completed   bool
+		StatusCode  int
+		// Header holds the JSON encoded response headers.
+		Header      string
+		Body        []byte
+		LockedUntil time.Time
+		ExpiresAt   time.Time `gorm:"index"`
+		CreatedAt   time.Time
+		UpdatedAt   time.Time
+	}
+)
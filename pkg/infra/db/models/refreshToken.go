@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+)
+
+type (
+	// RefreshToken struct represents a refresh token in the database. Only the hash of the token is stored.
+	RefreshToken struct {
+		ID        uint   `gorm:"primaryKey"`
+		TokenHash string `gorm:"uniqueIndex"`
+		// FamilyID is shared by the tokens rotated from the same login, so they are revoked together.
+		FamilyID  string `gorm:"index"`
+		UserID    uint   `gorm:"index"`
+		Username  string
+		ExpiresAt time.Time `gorm:"index"`
+		// RotatedAt is set when the token is exchanged for the next one of the family.
+		RotatedAt *time.Time
+		RevokedAt *time.Time
+		CreatedAt time.Time
+	}
+)
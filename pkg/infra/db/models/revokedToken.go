@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+)
+
+type (
+	// RevokedToken struct represents a denylist entry of the revoked access tokens in the database.
+	RevokedToken struct {
+		// Target is the revoked token ID prefixed with "jti:" or the subject prefixed with "sub:".
+		Target string `gorm:"primaryKey"`
+		// IssuedBefore denies the tokens of the subject issued before it; it is zero for the token IDs.
+		IssuedBefore time.Time
+		ExpiresAt    time.Time `gorm:"index"`
+		CreatedAt    time.Time
+	}
+)
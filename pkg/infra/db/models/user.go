@@ -12,5 +12,7 @@ type (
 		Surname string `json:"surname"`
 		Phone   string `json:"phone"`
 		Address string `json:"address"`
+		// Credential is the login credential of the user; nil if the user can't log in.
+		Credential *Credential `json:"-" gorm:"constraint:OnDelete:CASCADE"`
 	}
 )